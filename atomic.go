@@ -0,0 +1,96 @@
+package mapstructure
+
+import "reflect"
+
+// decodeAtomic decodes input into a deep copy of the result and only stores
+// the copy in the result if decoding and validation succeeded.
+func (d *Decoder) decodeAtomic(input interface{}) error {
+	result := reflect.ValueOf(d.config.Result).Elem()
+
+	scratch := reflect.New(result.Type()).Elem()
+	scratch.Set(deepCopyValue(result, make(map[uintptr]reflect.Value)))
+
+	err := d.decode("", input, scratch)
+	if err == nil && d.config.Validate {
+		err = d.validate("", scratch)
+	}
+	if err != nil {
+		return err
+	}
+
+	result.Set(scratch)
+	return nil
+}
+
+// deepCopyValue returns a copy of v that shares no pointers, maps or slices
+// with it, so that decoding into the copy can't modify v. Pointers seen
+// before are copied only once, which keeps cycles and shared values intact.
+// Unexported fields, channels and functions are copied shallowly since
+// decoding never writes to them.
+func deepCopyValue(v reflect.Value, seen map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := seen[v.Pointer()]; ok && c.Type() == v.Type() {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		seen[v.Pointer()] = c
+		c.Elem().Set(deepCopyValue(v.Elem(), seen))
+		return c
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopyValue(v.Elem(), seen))
+		return c
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), deepCopyValue(it.Value(), seen))
+		}
+		return c
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i), seen))
+		}
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			c.Field(i).Set(deepCopyValue(v.Field(i), seen))
+		}
+		return c
+	}
+
+	return v
+}
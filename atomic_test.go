@@ -0,0 +1,122 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+)
+
+type atomicNested struct {
+	Host string
+	Port int
+}
+
+type atomicConfig struct {
+	Name    string
+	Labels  map[string]string
+	Nested  *atomicNested
+	Servers []atomicNested
+	Count   int
+}
+
+func newAtomicConfig() atomicConfig {
+	return atomicConfig{
+		Name:    "old",
+		Labels:  map[string]string{"env": "prod"},
+		Nested:  &atomicNested{Host: "localhost", Port: 80},
+		Servers: []atomicNested{{Host: "a", Port: 1}},
+		Count:   1,
+	}
+}
+
+func TestDecoder_Atomic(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"name":    "new",
+		"labels":  map[string]interface{}{"team": "core"},
+		"nested":  map[string]interface{}{"port": 8080},
+		"servers": []interface{}{map[string]interface{}{"host": "b"}},
+		"count":   "not a number",
+	}
+
+	for _, zeroFields := range []bool{false, true} {
+		actual := newAtomicConfig()
+		nested := actual.Nested
+		labels := actual.Labels
+
+		decoder, err := NewDecoder(&DecoderConfig{
+			Result:     &actual,
+			Atomic:     true,
+			ZeroFields: zeroFields,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if err := decoder.Decode(input); err == nil {
+			t.Fatal("expected error")
+		}
+
+		expected := newAtomicConfig()
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("ZeroFields=%t: Decode() expected: %#v\ngot: %#v", zeroFields, expected, actual)
+		}
+		if actual.Nested != nested || !reflect.DeepEqual(labels, expected.Labels) {
+			t.Fatalf("ZeroFields=%t: references in the result were modified", zeroFields)
+		}
+	}
+}
+
+func TestDecoder_AtomicCommit(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"name":   "new",
+		"labels": map[string]interface{}{"team": "core"},
+		"nested": map[string]interface{}{"port": 8080},
+		"count":  2,
+	}
+
+	actual := newAtomicConfig()
+	decoder, err := NewDecoder(&DecoderConfig{Result: &actual, Atomic: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := atomicConfig{
+		Name:    "new",
+		Labels:  map[string]string{"env": "prod", "team": "core"},
+		Nested:  &atomicNested{Host: "localhost", Port: 8080},
+		Servers: []atomicNested{{Host: "a", Port: 1}},
+		Count:   2,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestDecoder_AtomicValidate(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Port int `validate:"max=1024"`
+	}
+
+	actual := Config{Port: 80}
+	decoder, err := NewDecoder(&DecoderConfig{Result: &actual, Atomic: true, Validate: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(map[string]interface{}{"port": 8080}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if actual.Port != 80 {
+		t.Fatalf("expected port to be unchanged, got %d", actual.Port)
+	}
+}
@@ -0,0 +1,577 @@
+// Command mapstructure-gen writes methods that decode maps into struct
+// types, and those types back into maps, without walking their fields
+// through reflection. Fields of basic types are assigned directly; other
+// fields, such as slices, maps and nested structs, are still handed to the
+// mapstructure.Decoder.
+//
+// It is meant to be run by go generate:
+//
+//	//go:generate go run github.com/CoverWhale/mapstructure/v2/cmd/mapstructure-gen -type Config,Server
+//
+// For every type it writes DecodeMapstructure(map[string]any) error and
+// EncodeMapstructure() map[string]any, which behave like decoding with a
+// mapstructure.Decoder configured by the flags, and the methods that let
+// any mapstructure.Decoder use the generated code automatically. See
+// mapstructure.GeneratedDecoder.
+//
+// Types using tag features that depend on the values being decoded, such as
+// aliases or squashed interfaces, are rejected with an error; they can
+// still be decoded through reflection.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// options are the command line flags.
+type options struct {
+	types             []string
+	output            string
+	tagName           string
+	squash            bool
+	ignoreUntagged    bool
+	weak              bool
+	errorUnused       bool
+	errorUnset        bool
+	allowUnsetPointer bool
+	zeroFields        bool
+}
+
+func main() {
+	var opts options
+	var types string
+	flag.StringVar(&types, "type", "", "comma-separated list of struct types to generate methods for (required)")
+	flag.StringVar(&opts.output, "output", "", "output file name (default <first type>_mapstructure.go)")
+	flag.StringVar(&opts.tagName, "tag", "mapstructure", "tag name holding the field names")
+	flag.BoolVar(&opts.squash, "squash", false, "squash embedded structs")
+	flag.BoolVar(&opts.ignoreUntagged, "ignore-untagged", false, "ignore fields without tags")
+	flag.BoolVar(&opts.weak, "weak", false, "decode with WeaklyTypedInput")
+	flag.BoolVar(&opts.errorUnused, "error-unused", false, "decode with ErrorUnused")
+	flag.BoolVar(&opts.errorUnset, "error-unset", false, "decode with ErrorUnset")
+	flag.BoolVar(&opts.allowUnsetPointer, "allow-unset-pointer", false, "decode with AllowUnsetPointer")
+	flag.BoolVar(&opts.zeroFields, "zero-fields", false, "decode with ZeroFields")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: mapstructure-gen -type T[,T...] [flags] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if types == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts.types = strings.Split(types, ",")
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+	if opts.output == "" {
+		opts.output = strings.ToLower(opts.types[0]) + "_mapstructure.go"
+	}
+	output := filepath.Join(dir, opts.output)
+
+	src, err := generate(dir, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mapstructure-gen: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(output, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "mapstructure-gen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate parses the package in dir and returns the formatted source of
+// the methods for the requested types.
+func generate(dir string, opts options) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != opts.output
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	g := &generator{
+		opts:  opts,
+		types: make(map[string]*ast.TypeSpec),
+	}
+	for _, pkg := range pkgs {
+		g.pkgName = pkg.Name
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					spec := spec.(*ast.TypeSpec)
+					g.types[spec.Name.Name] = spec
+				}
+			}
+		}
+	}
+
+	g.printf("// Code generated by mapstructure-gen; DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.pkgName)
+	g.printf("import \"github.com/CoverWhale/mapstructure/v2\"\n")
+
+	for _, name := range opts.types {
+		if err := g.generateType(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting output: %w", err)
+	}
+
+	return src, nil
+}
+
+// generator holds the state of a single run.
+type generator struct {
+	opts    options
+	pkgName string
+	types   map[string]*ast.TypeSpec
+	buf     bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// basicTypes are the types that are decoded and encoded without calling
+// into the Decoder when the input holds exactly that type.
+var basicTypes = map[string]bool{
+	"string": true, "bool": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// field is a struct field as seen by decoding or encoding.
+type field struct {
+	goName   string
+	expr     string
+	typ      ast.Expr
+	tagValue string
+	key      string
+	options  []string
+	exported bool
+	anon     bool
+}
+
+func (f *field) hasOption(option string) bool {
+	for _, o := range f.options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields returns the fields of the local struct type named name,
+// accessed through expr.
+func (g *generator) structFields(name, expr string) ([]*field, error) {
+	spec, ok := g.types[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", name)
+	}
+	if spec.TypeParams != nil {
+		return nil, fmt.Errorf("generic type %s is not supported", name)
+	}
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a struct type", name)
+	}
+
+	var fields []*field
+	for _, astField := range st.Fields.List {
+		var tag reflect.StructTag
+		if astField.Tag != nil {
+			unquoted, err := strconv.Unquote(astField.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+
+		names := make([]string, 0, len(astField.Names))
+		for _, n := range astField.Names {
+			names = append(names, n.Name)
+		}
+		anon := len(names) == 0
+		if anon {
+			names = append(names, embeddedName(astField.Type))
+		}
+
+		for _, goName := range names {
+			tagValue := tag.Get(g.opts.tagName)
+			parts := strings.Split(tagValue, ",")
+			f := &field{
+				goName:   goName,
+				expr:     expr + "." + goName,
+				typ:      astField.Type,
+				tagValue: tagValue,
+				key:      goName,
+				options:  parts[1:],
+				exported: ast.IsExported(goName),
+				anon:     anon,
+			}
+			if parts[0] != "" {
+				f.key = parts[0]
+			}
+			for _, o := range f.options {
+				switch {
+				case o == "", o == "omitempty", o == "omitzero", o == "squash", o == "remain", o == "sensitive":
+				case strings.HasPrefix(o, "alias="):
+					return nil, fmt.Errorf("field %s: aliases are not supported", goName)
+				case strings.HasPrefix(o, "prefix="):
+					return nil, fmt.Errorf("field %s: squash prefixes are not supported", goName)
+				case strings.HasPrefix(o, "keyby="):
+					return nil, fmt.Errorf("field %s: keyby is not supported", goName)
+				case o == "tuple", strings.HasPrefix(o, "index="):
+					return nil, fmt.Errorf("field %s: tuples are not supported", goName)
+				default:
+					return nil, fmt.Errorf("field %s: unsupported tag option %q", goName, o)
+				}
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	return fields, nil
+}
+
+// embeddedName returns the name of an embedded field of type typ.
+func embeddedName(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// localStruct returns the name of the local struct type typ refers to,
+// directly or through a pointer.
+func (g *generator) localStruct(typ ast.Expr) (name string, ptr bool, ok bool) {
+	if star, isStar := typ.(*ast.StarExpr); isStar {
+		typ, ptr = star.X, true
+	}
+	ident, isIdent := typ.(*ast.Ident)
+	if !isIdent {
+		return "", false, false
+	}
+	spec, found := g.types[ident.Name]
+	if !found {
+		return "", false, false
+	}
+	_, isStruct := spec.Type.(*ast.StructType)
+	return ident.Name, ptr, isStruct
+}
+
+// isSquashed reports whether f is squashed and checks that the generated
+// code can squash it the way the Decoder does.
+func (g *generator) isSquashed(f *field) (bool, error) {
+	_, ptr, local := g.localStruct(f.typ)
+	_, isLocalType := g.types[embeddedName(f.typ)]
+
+	squash := f.hasOption("squash")
+	if !squash && g.opts.squash && f.anon {
+		switch {
+		case local && !ptr:
+			squash = true
+		case local && ptr:
+			return false, fmt.Errorf("field %s: embedded pointers can't be squashed by configuration, use the squash tag option", f.goName)
+		case !isLocalType:
+			return false, fmt.Errorf("field %s: embedded types from other packages can't be squashed", f.goName)
+		}
+	}
+	if !squash {
+		return false, nil
+	}
+
+	switch {
+	case !local:
+		return false, fmt.Errorf("field %s: only struct types of the same package can be squashed", f.goName)
+	case !f.exported:
+		return false, fmt.Errorf("field %s: unexported fields can't be squashed", f.goName)
+	case f.hasOption("remain"), f.hasOption("omitempty"), f.hasOption("omitzero"):
+		return false, fmt.Errorf("field %s: squash can't be combined with other tag options", f.goName)
+	}
+
+	return true, nil
+}
+
+func (g *generator) generateType(name string) error {
+	if _, err := g.structFields(name, "t"); err != nil {
+		return err
+	}
+
+	layout := fmt.Sprintf("mapstructure.GeneratedLayout{TagName: %q, Squash: %t, IgnoreUntaggedFields: %t}",
+		g.opts.tagName, g.opts.squash, g.opts.ignoreUntagged)
+
+	g.printf("\n// MapstructureLayout implements mapstructure.GeneratedDecoder and\n")
+	g.printf("// mapstructure.GeneratedEncoder.\n")
+	g.printf("func (%s) MapstructureLayout() mapstructure.GeneratedLayout {\n", name)
+	g.printf("return %s\n}\n", layout)
+
+	config := g.configLiteral()
+	g.printf("\n// DecodeMapstructure decodes input into t.\n")
+	g.printf("func (t *%s) DecodeMapstructure(input map[string]any) error {\n", name)
+	g.printf("return mapstructure.DecodeGenerated(t, input, %s)\n}\n", config)
+
+	g.printf("\n// EncodeMapstructure returns t as a map.\n")
+	g.printf("func (t %s) EncodeMapstructure() map[string]any {\n", name)
+	g.printf("m, _ := mapstructure.EncodeGenerated(t, %s)\n", config)
+	g.printf("return m\n}\n")
+
+	if err := g.generateDecode(name); err != nil {
+		return err
+	}
+
+	return g.generateEncode(name)
+}
+
+// configLiteral returns the DecoderConfig the standalone methods use.
+func (g *generator) configLiteral() string {
+	var fields []string
+	if g.opts.tagName != "mapstructure" {
+		fields = append(fields, fmt.Sprintf("TagName: %q", g.opts.tagName))
+	}
+	for _, option := range []struct {
+		name string
+		set  bool
+	}{
+		{"Squash", g.opts.squash},
+		{"IgnoreUntaggedFields", g.opts.ignoreUntagged},
+		{"WeaklyTypedInput", g.opts.weak},
+		{"ErrorUnused", g.opts.errorUnused},
+		{"ErrorUnset", g.opts.errorUnset},
+		{"AllowUnsetPointer", g.opts.allowUnsetPointer},
+		{"ZeroFields", g.opts.zeroFields},
+	} {
+		if option.set {
+			fields = append(fields, option.name+": true")
+		}
+	}
+
+	return "&mapstructure.DecoderConfig{" + strings.Join(fields, ", ") + "}"
+}
+
+// generateDecode writes DecodeMapstructureWith, which follows
+// decodeStructFromMap: the fields of squashed structs are collected breadth
+// first and decoded after the fields of the outer struct.
+func (g *generator) generateDecode(name string) error {
+	var fields []*field
+	var allocs []string
+	var remain *field
+
+	type queued struct {
+		name string
+		expr string
+	}
+	structs := []queued{{name, "t"}}
+	for len(structs) > 0 {
+		s := structs[0]
+		structs = structs[1:]
+
+		structFields, err := g.structFields(s.name, s.expr)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range structFields {
+			squash, err := g.isSquashed(f)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case squash:
+				typeName, ptr, _ := g.localStruct(f.typ)
+				if ptr {
+					allocs = append(allocs, fmt.Sprintf("if %s == nil {\n%s = new(%s)\n}\n", f.expr, f.expr, typeName))
+				}
+				structs = append(structs, queued{typeName, f.expr})
+
+			case f.hasOption("remain"):
+				if _, ok := f.typ.(*ast.MapType); !ok {
+					return fmt.Errorf("field %s: remain fields must have a map type", f.goName)
+				}
+				remain = f
+
+			default:
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	g.printf("\n// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.\n")
+	g.printf("func (t *%s) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {\n", name)
+	g.printf("var errs []error\n")
+	g.printf("var unset []string\n")
+	g.printf("used := make([]string, 0, len(input))\n")
+	for _, f := range fields {
+		if f.exported && !(f.tagValue == "" && g.opts.ignoreUntagged) {
+			g.printf("prefix := \"\"\n")
+			g.printf("if name != \"\" {\nprefix = name + \".\"\n}\n")
+			break
+		}
+	}
+	for _, alloc := range allocs {
+		g.printf("\n%s", alloc)
+	}
+
+	for _, f := range fields {
+		if f.tagValue == "" && g.opts.ignoreUntagged {
+			continue
+		}
+
+		g.printf("\n")
+		if !f.exported {
+			// Unexported fields can't be set, but are reported as unset.
+			g.printf("if _, _, ok := g.Lookup(input, %q); !ok {\n", f.key)
+			g.printf("unset = %s\n}\n", g.unsetExpr(f))
+			continue
+		}
+
+		g.printf("if k, v, ok := g.Lookup(input, %q); ok {\n", f.key)
+		g.printf("used = append(used, k)\n")
+		decodeField := fmt.Sprintf("if err := g.DecodeField(prefix+%q, v, &%s); err != nil {\nerrs = append(errs, err)\n}\n", f.key, f.expr)
+		if basic := basicType(f.typ); basic != "" {
+			g.printf("if x, ok := v.(%s); ok {\n%s = x\n} else %s", basic, f.expr, decodeField)
+		} else {
+			g.printf("%s", decodeField)
+		}
+		g.printf("} else {\nunset = %s\n}\n", g.unsetExpr(f))
+	}
+
+	var names []string
+	for _, f := range fields {
+		if f.exported && f.key != "-" && !(f.tagValue == "" && g.opts.ignoreUntagged) {
+			names = append(names, strconv.Quote(f.key))
+		}
+	}
+	namesExpr := "nil"
+	if len(names) > 0 {
+		namesExpr = "[]string{" + strings.Join(names, ", ") + "}"
+	}
+
+	remainExpr := "nil"
+	if remain != nil {
+		remainExpr = "&" + remain.expr
+	}
+	g.printf("\nreturn g.Finish(name, input, %s, used, unset, %s, errs)\n}\n", namesExpr, remainExpr)
+
+	return nil
+}
+
+// unsetExpr returns the expression adding f to the unset fields.
+func (g *generator) unsetExpr(f *field) string {
+	name, ptr, local := g.localStruct(f.typ)
+	if basicType(f.typ) != "" || local && !ptr && name != "" {
+		return fmt.Sprintf("append(unset, %q)", f.key)
+	}
+
+	return fmt.Sprintf("g.Unset(unset, %q, &%s)", f.key, f.expr)
+}
+
+// basicType returns the name of typ if it is one of basicTypes.
+func basicType(typ ast.Expr) string {
+	if ident, ok := typ.(*ast.Ident); ok && basicTypes[ident.Name] {
+		return ident.Name
+	}
+	return ""
+}
+
+// generateEncode writes EncodeMapstructureWith, which follows
+// decodeMapFromStructField for every field in declaration order.
+func (g *generator) generateEncode(name string) error {
+	fields, err := g.structFields(name, "t")
+	if err != nil {
+		return err
+	}
+
+	g.printf("\n// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.\n")
+	g.printf("func (t %s) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {\n", name)
+	g.printf("m := make(map[string]interface{}, %d)\n", len(fields))
+	g.printf("var errs []error\n")
+
+	for _, f := range fields {
+		if !f.exported || f.tagValue == "" && g.opts.ignoreUntagged {
+			continue
+		}
+		if strings.Contains(f.tagValue, ",") && strings.Split(f.tagValue, ",")[0] == "-" || f.tagValue == "-" {
+			continue
+		}
+
+		squash, err := g.isSquashed(f)
+		if err != nil {
+			return err
+		}
+
+		g.printf("\n")
+		omitEmpty, omitZero := f.hasOption("omitempty"), f.hasOption("omitzero")
+		switch {
+		case squash:
+			g.printf("if err := g.EncodeSquash(name, %q, %q, &%s, m); err != nil {\nerrs = append(errs, err)\n}\n",
+				f.goName, f.key, f.expr)
+
+		case f.hasOption("remain"):
+			g.printf("for k, v := range %s {\nm[k] = v\n}\n", f.expr)
+
+		case basicType(f.typ) != "" && !strings.HasPrefix(basicType(f.typ), "float"):
+			basic := basicType(f.typ)
+			cond := ""
+			if omitEmpty || omitZero {
+				switch basic {
+				case "string":
+					cond = f.expr + ` != ""`
+				case "bool":
+					cond = f.expr
+				default:
+					cond = f.expr + " != 0"
+				}
+			}
+			if cond != "" {
+				g.printf("if %s {\nm[%q] = %s\n}\n", cond, f.key, f.expr)
+			} else {
+				g.printf("m[%q] = %s\n", f.key, f.expr)
+			}
+
+		case basicType(f.typ) != "" && !omitEmpty && !omitZero:
+			g.printf("m[%q] = %s\n", f.key, f.expr)
+
+		default:
+			g.printf("if v, ok, err := g.EncodeField(%q, &%s, %t, %t); err != nil {\nerrs = append(errs, err)\n} else if ok {\nm[%q] = v\n}\n",
+				f.key, f.expr, omitEmpty, omitZero, f.key)
+		}
+	}
+
+	g.printf("\nreturn m, g.Join(errs)\n}\n")
+
+	return nil
+}
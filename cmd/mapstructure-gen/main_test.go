@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("..", "..", "internal", "gentest")
+	cases := []struct {
+		output string
+		opts   options
+	}{
+		{
+			"fixtures_mapstructure.go",
+			options{types: fixtureTypes(t, dir, "fixtures_mapstructure.go")},
+		},
+		{
+			"squash_mapstructure.go",
+			options{types: fixtureTypes(t, dir, "squash_mapstructure.go"), squash: true},
+		},
+	}
+
+	for _, tc := range cases {
+		tc.opts.output = tc.output
+		tc.opts.tagName = "mapstructure"
+
+		src, err := generate(dir, tc.opts)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		expected, err := os.ReadFile(filepath.Join(dir, tc.output))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !bytes.Equal(src, expected) {
+			t.Fatalf("%s is out of date, run go generate", tc.output)
+		}
+	}
+}
+
+func TestGenerate_genSupport(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("..", "..", "internal", "gentest")
+	src, err := generate(dir, options{types: []string{"Nested"}, output: "nested_mapstructure.go", tagName: "mapstructure"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The generated code only reaches the Decoder through GenSupport.
+	for _, expected := range []string{
+		"DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error",
+		"EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error)",
+		"g.DecodeField(",
+		"g.EncodeField(",
+	} {
+		if !bytes.Contains(src, []byte(expected)) {
+			t.Errorf("expected %q in:\n%s", expected, src)
+		}
+	}
+	if bytes.Contains(src, []byte("*mapstructure.Decoder")) {
+		t.Errorf("unexpected *mapstructure.Decoder in:\n%s", src)
+	}
+}
+
+// fixtureTypes returns the types listed in the go:generate directive that
+// writes output.
+func fixtureTypes(t *testing.T, dir, output string) []string {
+	src, err := os.ReadFile(filepath.Join(dir, "fixtures.go"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, line := range strings.Split(string(src), "\n") {
+		if !strings.HasPrefix(line, "//go:generate") || !strings.Contains(line, "-output "+output) {
+			continue
+		}
+		fields := strings.Fields(line)
+		for i, f := range fields {
+			if f == "-type" && i+1 < len(fields) {
+				return strings.Split(fields[i+1], ",")
+			}
+		}
+	}
+
+	t.Fatalf("no go:generate directive for %s", output)
+	return nil
+}
+
+func TestGenerate_unsupported(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		src    string
+		squash bool
+		err    string
+	}{
+		{
+			"alias",
+			"type T struct {\n\tA string `mapstructure:\"a,alias=b\"`\n}",
+			false,
+			"aliases are not supported",
+		},
+		{
+			"prefix",
+			"type S struct {\n\tA string\n}\n\ntype T struct {\n\tS `mapstructure:\",squash,prefix=s_\"`\n}",
+			false,
+			"squash prefixes are not supported",
+		},
+		{
+			"keyby",
+			"type T struct {\n\tA map[string]string `mapstructure:\"a,keyby=name\"`\n}",
+			false,
+			"keyby is not supported",
+		},
+		{
+			"tuple",
+			"type T struct {\n\t_ struct{} `mapstructure:\",tuple\"`\n\tA string `mapstructure:\"a\"`\n}",
+			false,
+			"tuples are not supported",
+		},
+		{
+			"tuple index",
+			"type T struct {\n\tA string `mapstructure:\"a,index=1\"`\n}",
+			false,
+			"tuples are not supported",
+		},
+		{
+			"unknown option",
+			"type T struct {\n\tA string `mapstructure:\"a,what\"`\n}",
+			false,
+			`unsupported tag option "what"`,
+		},
+		{
+			"squash interface",
+			"type T struct {\n\tI interface{} `mapstructure:\",squash\"`\n}",
+			false,
+			"only struct types of the same package can be squashed",
+		},
+		{
+			"squash embedded pointer",
+			"type E struct{ A string }\n\ntype T struct {\n\t*E\n}",
+			true,
+			"embedded pointers can't be squashed by configuration",
+		},
+		{
+			"squash with omitempty",
+			"type E struct{ A string }\n\ntype T struct {\n\tE `mapstructure:\",squash,omitempty\"`\n}",
+			false,
+			"squash can't be combined with other tag options",
+		},
+		{
+			"remain not a map",
+			"type T struct {\n\tRest []string `mapstructure:\",remain\"`\n}",
+			false,
+			"remain fields must have a map type",
+		},
+		{
+			"not a struct",
+			"type T []string",
+			false,
+			"T is not a struct type",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package p\n\n"+tc.src+"\n"), 0o644); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			_, err := generate(dir, options{types: []string{"T"}, tagName: "mapstructure", squash: tc.squash})
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Command mapstructurevet reports mistakes in mapstructure struct tags.
+// It lives in its own module so that mapstructure itself keeps having no
+// dependencies.
+//
+//	go install github.com/CoverWhale/mapstructure/v2/cmd/mapstructurevet@latest
+//
+// It can be used as a vet tool:
+//
+//	go vet -vettool=$(which mapstructurevet) ./...
+//
+// or run directly:
+//
+//	mapstructurevet [-tag name] [-squash] ./...
+//
+// See the tagcheck package for the checks it runs.
+package main
+
+import (
+	"github.com/CoverWhale/mapstructure/v2/cmd/mapstructurevet/tagcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(tagcheck.Analyzer)
+}
@@ -0,0 +1,322 @@
+// Package tagcheck defines an Analyzer that reports mistakes in mapstructure
+// struct tags, which otherwise only show up when decoding, or never.
+//
+// It reports:
+//
+//   - the "remain" option on fields that aren't maps
+//   - the squash option on fields that aren't structs or pointers to structs
+//   - the "prefix" option on fields that aren't squashed
+//   - the "keyby" option on fields that aren't maps, slices or arrays
+//   - the "tuple" option on fields that aren't structs or slices of structs
+//   - "index" options that aren't non-negative integers
+//   - unknown tag options, such as "omitempy"
+//   - fields that resolve to the same key once embedded structs are squashed
+//   - tags on unexported fields, which are skipped
+//
+// The tag name, the squash option and whether embedded structs are squashed
+// as with DecoderConfig.Squash can be set with the analyzer flags -tag,
+// -squash-option and -squash.
+package tagcheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports mistakes in mapstructure struct tags.
+var Analyzer = &analysis.Analyzer{
+	Name:     "mapstructuretag",
+	Doc:      "check mapstructure struct tags for mistakes",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var (
+	tagName      string
+	squashOption string
+	squash       bool
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&tagName, "tag", "mapstructure", "tag name read by mapstructure, as DecoderConfig.TagName")
+	Analyzer.Flags.StringVar(&squashOption, "squash-option", "squash", "tag option squashing a field, as DecoderConfig.SquashTagOption")
+	Analyzer.Flags.BoolVar(&squash, "squash", false, "squash embedded structs, as DecoderConfig.Squash")
+}
+
+// knownOptions are the tag options understood by mapstructure, besides
+// the squash option.
+var knownOptions = []string{"omitempty", "omitzero", "remain", "sensitive"}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(n ast.Node) {
+		structType := n.(*ast.StructType)
+		typ, ok := pass.TypesInfo.Types[structType].Type.(*types.Struct)
+		if !ok {
+			return
+		}
+
+		tagged := false
+		for i := 0; i < typ.NumFields(); i++ {
+			if _, ok := reflect.StructTag(typ.Tag(i)).Lookup(tagName); ok {
+				tagged = true
+				checkField(pass, fieldNode(structType, i), typ.Field(i), typ.Tag(i))
+			}
+		}
+
+		// Structs that don't use the tag at all are left alone, since
+		// there's no telling whether they are ever decoded.
+		if tagged {
+			checkKeys(pass, structType, typ)
+		}
+	})
+
+	return nil, nil
+}
+
+// fieldNode returns the node declaring the i-th field of a struct.
+func fieldNode(structType *ast.StructType, i int) ast.Node {
+	for _, field := range structType.Fields.List {
+		names := len(field.Names)
+		if names == 0 {
+			names = 1
+		}
+		if i < names {
+			if len(field.Names) > 0 {
+				return field.Names[i]
+			}
+			return field
+		}
+		i -= names
+	}
+
+	return structType
+}
+
+func checkField(pass *analysis.Pass, node ast.Node, field *types.Var, tag string) {
+	value := reflect.StructTag(tag).Get(tagName)
+	parts := strings.Split(value, ",")
+
+	if !field.Exported() {
+		// A blank field with the "tuple" option marks its struct as a
+		// tuple.
+		if field.Name() == "_" && parts[0] == "" && contains(parts[1:], "tuple") {
+			return
+		}
+		if parts[0] != "-" {
+			pass.Reportf(node.Pos(), "%s tag on unexported field %s has no effect", tagName, field.Name())
+		}
+		return
+	}
+
+	for _, option := range parts[1:] {
+		switch {
+		case option == "":
+		case option == squashOption:
+			if !isStruct(field.Type()) && !isInterface(field.Type()) {
+				pass.Reportf(node.Pos(), "%s option on field %s of non-struct type %s", squashOption, field.Name(), field.Type())
+			}
+		case option == "remain":
+			if _, ok := field.Type().Underlying().(*types.Map); !ok {
+				pass.Reportf(node.Pos(), "remain option on field %s of non-map type %s", field.Name(), field.Type())
+			}
+		case strings.HasPrefix(option, "alias="):
+		case strings.HasPrefix(option, "keyby="):
+			switch field.Type().Underlying().(type) {
+			case *types.Map, *types.Slice, *types.Array:
+			default:
+				pass.Reportf(node.Pos(), "keyby option on field %s of type %s, which is neither a map nor a slice", field.Name(), field.Type())
+			}
+		case option == "tuple":
+			if !isStruct(field.Type()) && !isStruct(elemOf(field.Type())) {
+				pass.Reportf(node.Pos(), "tuple option on field %s of type %s, which is neither a struct nor a slice of structs", field.Name(), field.Type())
+			}
+		case strings.HasPrefix(option, "index="):
+			if index, err := strconv.Atoi(strings.TrimPrefix(option, "index=")); err != nil || index < 0 {
+				pass.Reportf(node.Pos(), "invalid index option %q on field %s", option, field.Name())
+			}
+		case strings.HasPrefix(option, "prefix="):
+			if !contains(parts[1:], squashOption) && !(squash && field.Anonymous()) {
+				pass.Reportf(node.Pos(), "prefix option on field %s has no effect without %s", field.Name(), squashOption)
+			}
+		case contains(knownOptions, option):
+		default:
+			if suggestion := suggestOption(option); suggestion != "" {
+				pass.Reportf(node.Pos(), "unknown %s option %q on field %s, did you mean %q?", tagName, option, field.Name(), suggestion)
+			} else {
+				pass.Reportf(node.Pos(), "unknown %s option %q on field %s", tagName, option, field.Name())
+			}
+		}
+	}
+}
+
+// key is a map key a struct field is decoded from.
+type key struct {
+	name  string
+	field string
+}
+
+// checkKeys reports fields of typ that are decoded from the same key once
+// squashed structs are flattened into it.
+func checkKeys(pass *analysis.Pass, structType *ast.StructType, typ *types.Struct) {
+	var seen []key
+	for i := 0; i < typ.NumFields(); i++ {
+		for _, k := range fieldKeys(typ.Field(i), typ.Tag(i), "", "", map[*types.Struct]bool{typ: true}) {
+			for _, other := range seen {
+				if strings.EqualFold(k.name, other.name) {
+					pass.Reportf(fieldNode(structType, i).Pos(), "%s key %q of field %s collides with field %s", tagName, k.name, k.field, other.field)
+					break
+				}
+			}
+			seen = append(seen, k)
+		}
+	}
+}
+
+// fieldKeys returns the keys a field is decoded from: its name and aliases,
+// or those of the fields of the struct it squashes, with prefix in front.
+func fieldKeys(field *types.Var, tag string, path, prefix string, visiting map[*types.Struct]bool) []key {
+	value, tagged := reflect.StructTag(tag).Lookup(tagName)
+	parts := strings.Split(value, ",")
+	if path != "" {
+		path += "."
+	}
+	path += field.Name()
+
+	if !field.Exported() || parts[0] == "-" {
+		return nil
+	}
+
+	isSquashed := squash && field.Anonymous() && isStruct(field.Type())
+	for _, option := range parts[1:] {
+		if option == "remain" {
+			return nil
+		}
+		if option == squashOption {
+			isSquashed = true
+		}
+	}
+
+	squashPrefix := prefix
+	for _, option := range parts[1:] {
+		if strings.HasPrefix(option, "prefix=") {
+			squashPrefix += strings.TrimPrefix(option, "prefix=")
+		}
+	}
+
+	if isSquashed {
+		st, ok := structOf(field.Type())
+		if !ok || visiting[st] {
+			return nil
+		}
+		visiting[st] = true
+		defer delete(visiting, st)
+
+		var keys []key
+		for i := 0; i < st.NumFields(); i++ {
+			keys = append(keys, fieldKeys(st.Field(i), st.Tag(i), path, squashPrefix, visiting)...)
+		}
+		return keys
+	}
+
+	name := field.Name()
+	if tagged && parts[0] != "" {
+		name = parts[0]
+	}
+	keys := []key{{prefix + name, path}}
+	for _, option := range parts[1:] {
+		if strings.HasPrefix(option, "alias=") {
+			for _, alias := range strings.Split(strings.TrimPrefix(option, "alias="), "|") {
+				keys = append(keys, key{prefix + alias, path})
+			}
+		}
+	}
+
+	return keys
+}
+
+func structOf(typ types.Type) (*types.Struct, bool) {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	return st, ok
+}
+
+// elemOf returns the element type of a slice or array type, or typ itself.
+func elemOf(typ types.Type) types.Type {
+	switch t := typ.Underlying().(type) {
+	case *types.Slice:
+		return t.Elem()
+	case *types.Array:
+		return t.Elem()
+	}
+	return typ
+}
+
+func isStruct(typ types.Type) bool {
+	_, ok := structOf(typ)
+	return ok
+}
+
+func isInterface(typ types.Type) bool {
+	return types.IsInterface(typ)
+}
+
+func contains(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestOption returns the known option closest to a misspelled one.
+func suggestOption(option string) string {
+	best, bestDistance := "", 3
+	for _, known := range append([]string{squashOption}, knownOptions...) {
+		if d := levenshtein(option, known); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,27 @@
+package tagcheck_test
+
+import (
+	"testing"
+
+	"github.com/CoverWhale/mapstructure/v2/cmd/mapstructurevet/tagcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), tagcheck.Analyzer, "a")
+}
+
+func TestAnalyzer_flags(t *testing.T) {
+	flags := tagcheck.Analyzer.Flags
+	for name, value := range map[string]string{"tag": "conf", "squash": "true"} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+	defer func() {
+		_ = flags.Set("tag", "mapstructure")
+		_ = flags.Set("squash", "false")
+	}()
+
+	analysistest.Run(t, analysistest.TestData(), tagcheck.Analyzer, "b")
+}
@@ -0,0 +1,63 @@
+package a
+
+type Inner struct {
+	Name string `mapstructure:"name"`
+	Port int    `mapstructure:"port"`
+}
+
+type Valid struct {
+	Inner  `mapstructure:",squash"`
+	Host   string                 `mapstructure:"host,omitempty"`
+	Secret string                 `mapstructure:"secret,sensitive"`
+	Old    string                 `mapstructure:"old,alias=legacy|older"`
+	Extra  map[string]interface{} `mapstructure:",remain"`
+	Ignore string                 `mapstructure:"-"`
+	hidden string                 `mapstructure:"-"`
+	Any    interface{}            `mapstructure:",squash"`
+}
+
+type Invalid struct {
+	Rest    []string `mapstructure:",remain"`     // want `remain option on field Rest of non-map type \[\]string`
+	Count   int      `mapstructure:",squash"`     // want `squash option on field Count of non-struct type int`
+	Options string   `mapstructure:",omitempy"`   // want `unknown mapstructure option "omitempy" on field Options, did you mean "omitempty"\?`
+	Other   string   `mapstructure:",frobnicate"` // want `unknown mapstructure option "frobnicate" on field Other`
+	private string   `mapstructure:"private"`     // want `mapstructure tag on unexported field private has no effect`
+}
+
+type Collision struct {
+	Inner `mapstructure:",squash"`
+	Name  string `mapstructure:"name"`             // want `mapstructure key "name" of field Name collides with field Inner.Name`
+	Alias string `mapstructure:"alias,alias=PORT"` // want `mapstructure key "PORT" of field Alias collides with field Inner.Port`
+}
+
+type Untagged struct {
+	Inner
+	Name string
+}
+
+type Prefixed struct {
+	Inner  `mapstructure:",squash"`
+	Admin  Inner  `mapstructure:",squash,prefix=admin_"`
+	Plain  Inner  `mapstructure:"plain,prefix=plain_"` // want `prefix option on field Plain has no effect without squash`
+	Port   int    `mapstructure:"admin_port"`          // want `mapstructure key "admin_port" of field Port collides with field Admin.Port`
+	Unique string `mapstructure:"admin_host"`
+}
+
+type Keyed struct {
+	Servers map[string]Inner `mapstructure:"servers,keyby=name"`
+	List    []Inner          `mapstructure:"list,keyby=name"`
+	Single  Inner            `mapstructure:"single,keyby=name"` // want `keyby option on field Single of type a.Inner, which is neither a map nor a slice`
+}
+
+type Point struct {
+	_   struct{} `mapstructure:",tuple"`
+	Lat float64  `mapstructure:"lat"`
+	Lon float64  `mapstructure:"lon,index=1"`
+	Alt float64  `mapstructure:"alt,index=two,omitempty"` // want `invalid index option "index=two" on field Alt`
+}
+
+type Route struct {
+	Start  Inner   `mapstructure:"start,tuple"`
+	Points []Inner `mapstructure:"points,tuple"`
+	Name   string  `mapstructure:"name,tuple"` // want `tuple option on field Name of type string, which is neither a struct nor a slice of structs`
+}
@@ -0,0 +1,12 @@
+package b
+
+type Inner struct {
+	Name string `conf:"name"`
+}
+
+type Config struct {
+	Inner
+	Name  string   `conf:"name"`                             // want `conf key "name" of field Name collides with field Inner.Name`
+	Rest  []string `conf:",remain"`                          // want `remain option on field Rest of non-map type \[\]string`
+	Other string   `conf:",inline" mapstructure:",omitempy"` // want `unknown conf option "inline" on field Other`
+}
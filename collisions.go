@@ -0,0 +1,223 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// KeyCollisionPolicy decides what happens when more than one field of a
+// struct resolves to the same key once squashed structs are flattened into
+// it, such as a field Name next to a squashed struct that also has a field
+// Name.
+type KeyCollisionPolicy int
+
+const (
+	// KeyCollisionIgnore keeps the behaviour of earlier versions: decoding
+	// a map sets the value on every colliding field, and decoding a struct
+	// into a map keeps the value of the field that comes last.
+	KeyCollisionIgnore KeyCollisionPolicy = iota
+
+	// KeyCollisionError reports an error naming the colliding fields
+	// whenever the key they share is decoded, in either direction.
+	KeyCollisionError
+
+	// KeyCollisionShallowest follows the way Go resolves embedded fields:
+	// the field with the fewest squashed structs between it and the outer
+	// struct wins and the others are ignored. If several fields share the
+	// shallowest depth, all of them are ignored.
+	KeyCollisionShallowest
+)
+
+// collidingField is a field sharing its key with other fields of a struct.
+// path is the path of Go field names leading to it from the outer struct.
+type collidingField struct {
+	path  string
+	depth int
+}
+
+// keyCollisions returns the fields of struct type typ that share a key once
+// squashed structs are flattened, by key. Fields are ordered the way
+// decodeStructFromMap visits them, shallowest first. Pointers to structs
+// embedded with Squash enabled are only followed when decoding into the
+// struct, as decodeMapFromStruct doesn't squash them.
+//
+// When decoding into the struct, keys matched by MatchName share a group,
+// since any of the fields can be decoded from the same input key, and the
+// group is returned under each of its keys. Keys of a map only collide if
+// they are exactly the same.
+func (d *Decoder) keyCollisions(typ reflect.Type, decoding bool) map[string][]collidingField {
+	type queued struct {
+		typ    reflect.Type
+		path   string
+		prefix string
+		depth  int
+	}
+
+	// groups holds the keys of each group along with its fields.
+	type group struct {
+		keys   []string
+		fields []collidingField
+	}
+
+	var groups []*group
+	byKey := make(map[string]*group)
+	structs := []queued{{typ: typ}}
+	for len(structs) > 0 {
+		s := structs[0]
+		structs = structs[1:]
+
+		for i := 0; i < s.typ.NumField(); i++ {
+			f := s.typ.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			path := f.Name
+			if s.path != "" {
+				path = s.path + "." + f.Name
+			}
+
+			fieldType := f.Type
+			isStructPtr := fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct
+			squash := d.config.Squash && f.Anonymous &&
+				(fieldType.Kind() == reflect.Struct || decoding && isStructPtr)
+			remain := false
+
+			tagValue := f.Tag.Get(d.config.TagName)
+			tagParts := strings.Split(tagValue, ",")
+			for _, tag := range tagParts[1:] {
+				if tag == d.config.SquashTagOption {
+					squash = true
+				}
+				if tag == "remain" {
+					remain = true
+				}
+			}
+
+			if squash {
+				if isStructPtr {
+					fieldType = fieldType.Elem()
+				}
+				if fieldType.Kind() == reflect.Struct {
+					structs = append(structs, queued{fieldType, path, s.prefix + tagPrefix(tagParts[1:]), s.depth + 1})
+				}
+				continue
+			}
+
+			if remain || tagParts[0] == "-" || tagValue == "" && d.config.IgnoreUntaggedFields {
+				continue
+			}
+
+			keyName := f.Name
+			if tagParts[0] != "" {
+				keyName = tagParts[0]
+			}
+			keyName = s.prefix + keyName
+
+			g, ok := byKey[keyName]
+			if !ok && decoding {
+				for _, other := range groups {
+					if d.config.MatchName(other.keys[0], keyName) {
+						g = other
+						break
+					}
+				}
+			}
+			if g == nil {
+				g = &group{}
+				groups = append(groups, g)
+			}
+			if !ok {
+				g.keys = append(g.keys, keyName)
+				byKey[keyName] = g
+			}
+			g.fields = append(g.fields, collidingField{path, s.depth})
+		}
+	}
+
+	fields := make(map[string][]collidingField)
+	for _, g := range groups {
+		if len(g.fields) < 2 {
+			continue
+		}
+		for _, k := range g.keys {
+			fields[k] = g.fields
+		}
+	}
+
+	return fields
+}
+
+// shallowest returns the path of the field that wins a collision under
+// KeyCollisionShallowest, or "" if the shallowest fields are tied.
+func shallowest(group []collidingField) string {
+	if len(group) > 1 && group[1].depth == group[0].depth {
+		return ""
+	}
+
+	return group[0].path
+}
+
+// collisionError returns the error for the fields of group colliding on
+// key.
+func collisionError(name, key string, group []collidingField) error {
+	paths := make([]string, len(group))
+	for i, f := range group {
+		paths[i] = f.path
+	}
+
+	return newDecodeError(name, fmt.Errorf("has conflicting fields for key %q: %s", key, strings.Join(paths, ", ")))
+}
+
+// structKeyClaims applies KeyCollisions while decodeMapFromStruct puts the
+// fields of a single struct into a map.
+type structKeyClaims struct {
+	decoder    *Decoder
+	name       string
+	collisions map[string][]collidingField
+	written    map[string]bool
+}
+
+// newStructKeyClaims returns the claims for decoding a struct of type typ
+// into a map, or nil if KeyCollisions is KeyCollisionIgnore.
+func (d *Decoder) newStructKeyClaims(name string, typ reflect.Type) *structKeyClaims {
+	if d.config.KeyCollisions == KeyCollisionIgnore {
+		return nil
+	}
+
+	return &structKeyClaims{
+		decoder:    d,
+		name:       name,
+		collisions: d.keyCollisions(typ, false),
+		written:    make(map[string]bool),
+	}
+}
+
+// claim reports whether the field at path, or the squashed struct at path
+// if squashed is set, may put key into the map.
+func (c *structKeyClaims) claim(key, path string, squashed bool) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	group, ok := c.collisions[key]
+	if !ok {
+		return true, nil
+	}
+
+	if c.decoder.config.KeyCollisions == KeyCollisionShallowest {
+		winner := shallowest(group)
+		if squashed {
+			return strings.HasPrefix(winner, path+"."), nil
+		}
+		return winner == path, nil
+	}
+
+	if c.written[key] {
+		return false, collisionError(c.name, key, group)
+	}
+	c.written[key] = true
+
+	return true, nil
+}
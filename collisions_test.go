@@ -0,0 +1,304 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type CollisionInner struct {
+	Name string
+	Port int
+}
+
+type CollisionOther struct {
+	Name string
+	Host string
+}
+
+type CollisionParent struct {
+	CollisionInner `mapstructure:",squash"`
+	Name           string
+}
+
+type CollisionSiblings struct {
+	CollisionInner `mapstructure:",squash"`
+	CollisionOther `mapstructure:",squash"`
+}
+
+func TestDecode_keyCollisionsIgnore(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{"Name": "foo", "Port": 80}
+
+	var result CollisionParent
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := CollisionParent{CollisionInner: CollisionInner{Name: "foo", Port: 80}, Name: "foo"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_keyCollisionsShallowest(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		input    map[string]interface{}
+		result   interface{}
+		expected interface{}
+		unused   []string
+	}{
+		{
+			name:     "parent wins",
+			input:    map[string]interface{}{"Name": "foo", "Port": 80},
+			result:   &CollisionParent{},
+			expected: &CollisionParent{CollisionInner: CollisionInner{Port: 80}, Name: "foo"},
+		},
+		{
+			name:     "siblings tie",
+			input:    map[string]interface{}{"Name": "foo", "Port": 80, "Host": "localhost"},
+			result:   &CollisionSiblings{},
+			expected: &CollisionSiblings{CollisionInner: CollisionInner{Port: 80}, CollisionOther: CollisionOther{Host: "localhost"}},
+			unused:   []string{"Name"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var md Metadata
+			decoder, err := NewDecoder(&DecoderConfig{
+				KeyCollisions: KeyCollisionShallowest,
+				Metadata:      &md,
+				Result:        tc.result,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if err := decoder.Decode(tc.input); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(tc.result, tc.expected) {
+				t.Fatalf("Decode() expected: %#v\ngot: %#v", tc.expected, tc.result)
+			}
+			if len(md.Unused) != len(tc.unused) || len(tc.unused) > 0 && !reflect.DeepEqual(md.Unused, tc.unused) {
+				t.Fatalf("expected unused %v, got %v", tc.unused, md.Unused)
+			}
+		})
+	}
+}
+
+func TestDecode_keyCollisionsError(t *testing.T) {
+	t.Parallel()
+
+	decode := func(input map[string]interface{}, result interface{}) error {
+		decoder, err := NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionError, Result: result})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return decoder.Decode(input)
+	}
+
+	err := decode(map[string]interface{}{"Name": "foo"}, &CollisionParent{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `has conflicting fields for key "Name": Name, CollisionInner.Name`) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = decode(map[string]interface{}{"Name": "foo"}, &CollisionSiblings{})
+	if err == nil || !strings.Contains(err.Error(), "CollisionInner.Name, CollisionOther.Name") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Collisions only matter if the key is present.
+	if err := decode(map[string]interface{}{"Port": 80}, &CollisionParent{}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDecode_keyCollisionsToMap(t *testing.T) {
+	t.Parallel()
+
+	parent := CollisionParent{CollisionInner: CollisionInner{Name: "inner", Port: 80}, Name: "outer"}
+	siblings := CollisionSiblings{
+		CollisionInner: CollisionInner{Name: "inner", Port: 80},
+		CollisionOther: CollisionOther{Name: "other", Host: "localhost"},
+	}
+
+	cases := []struct {
+		name     string
+		policy   KeyCollisionPolicy
+		input    interface{}
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "ignore keeps the last field",
+			policy:   KeyCollisionIgnore,
+			input:    parent,
+			expected: map[string]interface{}{"Name": "outer", "Port": 80},
+		},
+		{
+			name:     "ignore keeps the last squashed field",
+			policy:   KeyCollisionIgnore,
+			input:    siblings,
+			expected: map[string]interface{}{"Name": "other", "Port": 80, "Host": "localhost"},
+		},
+		{
+			name:     "shallowest",
+			policy:   KeyCollisionShallowest,
+			input:    parent,
+			expected: map[string]interface{}{"Name": "outer", "Port": 80},
+		},
+		{
+			name:     "shallowest tie",
+			policy:   KeyCollisionShallowest,
+			input:    siblings,
+			expected: map[string]interface{}{"Port": 80, "Host": "localhost"},
+		},
+		{
+			name:   "error",
+			policy: KeyCollisionError,
+			input:  parent,
+			err:    `has conflicting fields for key "Name": Name, CollisionInner.Name`,
+		},
+		{
+			name:   "error between squashed structs",
+			policy: KeyCollisionError,
+			input:  siblings,
+			err:    `has conflicting fields for key "Name": CollisionInner.Name, CollisionOther.Name`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result map[string]interface{}
+			decoder, err := NewDecoder(&DecoderConfig{KeyCollisions: tc.policy, Result: &result})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			err = decoder.Decode(tc.input)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Decode() expected: %#v\ngot: %#v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestDecode_keyCollisionsStructToStruct(t *testing.T) {
+	t.Parallel()
+
+	input := CollisionSiblings{
+		CollisionInner: CollisionInner{Name: "inner", Port: 80},
+		CollisionOther: CollisionOther{Name: "other", Host: "localhost"},
+	}
+
+	var result CollisionParent
+	decoder, err := NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionShallowest, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := CollisionParent{CollisionInner: CollisionInner{Port: 80}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_keyCollisionsMatchName(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		Name string `mapstructure:"name"`
+		Port int    `mapstructure:"port"`
+	}
+
+	type Parent struct {
+		Inner `mapstructure:",squash"`
+		Name  string
+	}
+
+	input := map[string]interface{}{"name": "foo", "port": 80}
+
+	var result Parent
+	decoder, err := NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionShallowest, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Parent{Inner: Inner{Port: 80}, Name: "foo"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	decoder, err = NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionError, Result: &Parent{}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = decoder.Decode(input)
+	if err == nil || !strings.Contains(err.Error(), `has conflicting fields for key "Name": Name, Inner.Name`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A case-sensitive MatchName keeps the keys apart.
+	result = Parent{}
+	decoder, err = NewDecoder(&DecoderConfig{
+		KeyCollisions: KeyCollisionError,
+		MatchName:     func(mapKey, fieldName string) bool { return mapKey == fieldName },
+		Result:        &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected = Parent{Inner: Inner{Name: "foo", Port: 80}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// In a map the keys don't collide.
+	var m map[string]interface{}
+	decoder, err = NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionError, Result: &m})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(Parent{Inner: Inner{Name: "inner"}, Name: "outer"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedMap := map[string]interface{}{"name": "inner", "port": 0, "Name": "outer"}
+	if !reflect.DeepEqual(m, expectedMap) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expectedMap, m)
+	}
+}
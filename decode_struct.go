@@ -0,0 +1,454 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// structPlanKey identifies the plan for decoding one struct type into
+// another.
+type structPlanKey struct {
+	src reflect.Type
+	dst reflect.Type
+}
+
+// structPlan describes how the fields of a source struct type are decoded
+// into the fields of a target struct type. Plans are resolved once per type
+// pair and cached on the Decoder, so that decoding a struct into another
+// struct doesn't have to go through an intermediate map.
+//
+// The plan mirrors the keys decodeMapFromStruct would produce for the
+// source and the way decodeStructFromMap would look them up for the target.
+type structPlan struct {
+	src []structPlanSrc
+	dst []structPlanDst
+}
+
+// structPlanSrc is a field of the source struct, as it would appear as a
+// key in the map built by decodeMapFromStruct.
+type structPlanSrc struct {
+	index     []int
+	key       string
+	omitEmpty bool
+	omitZero  bool
+}
+
+// structPlanDst is a field of the target struct.
+type structPlanDst struct {
+	index []int
+	name  string
+
+	// candidates holds the indexes of the source fields matching this
+	// field, in the order decodeStructFromMap would prefer them. Source
+	// fields can be omitted at runtime, so the first present one wins.
+	candidates []int
+}
+
+// structPlan returns the plan for decoding src into dst, or nil if the
+// types use features that depend on the values being decoded. In that case
+// the struct must be decoded through an intermediate map instead.
+func (d *Decoder) structPlan(src, dst reflect.Type) *structPlan {
+	key := structPlanKey{src: src, dst: dst}
+	if plan, ok := d.structPlans.Load(key); ok {
+		return plan.(*structPlan)
+	}
+
+	plan := d.buildStructPlan(src, dst)
+	d.structPlans.Store(key, plan)
+	return plan
+}
+
+func (d *Decoder) buildStructPlan(src, dst reflect.Type) *structPlan {
+	plan := &structPlan{}
+
+	// Encode hooks see the source fields as they are put into the map.
+	if d.config.EncodeHook != nil {
+		return nil
+	}
+
+	// Path hooks see the names of the values nested in squashed and
+	// nested structs on their way into the map.
+	if len(d.pathHooks) > 0 {
+		return nil
+	}
+
+	// Colliding keys are resolved while going through the map.
+	if d.config.KeyCollisions != KeyCollisionIgnore {
+		return nil
+	}
+
+	keys := make(map[string]int)
+	if !d.planStructSource(src, nil, keys, plan) {
+		return nil
+	}
+
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	// The fields of the target are collected breadth first, just like
+	// decodeStructFromMap does.
+	structs := []queued{{typ: dst}}
+	for len(structs) > 0 {
+		structType, structIndex := structs[0].typ, structs[0].index
+		structs = structs[1:]
+
+		for i := 0; i < structType.NumField(); i++ {
+			fieldType := structType.Field(i)
+			fieldIndex := append(append([]int(nil), structIndex...), i)
+			if d.isTupleMarker(fieldType) {
+				continue
+			}
+
+			isStructPtr := fieldType.Type.Kind() == reflect.Ptr && fieldType.Type.Elem().Kind() == reflect.Struct
+			squash := d.config.Squash && fieldType.Type.Kind() == reflect.Struct && fieldType.Anonymous
+			if d.config.Squash && fieldType.Anonymous && isStructPtr {
+				// Whether this is squashed depends on the pointer being nil.
+				return nil
+			}
+
+			tagParts := strings.Split(fieldType.Tag.Get(d.config.TagName), ",")
+			for _, tag := range tagParts[1:] {
+				if tag == d.config.SquashTagOption {
+					squash = true
+					break
+				}
+
+				if tag == "remain" {
+					return nil
+				}
+			}
+
+			if squash {
+				if fieldType.Type.Kind() != reflect.Struct || tagPrefix(tagParts[1:]) != "" {
+					return nil
+				}
+
+				structs = append(structs, queued{typ: fieldType.Type, index: fieldIndex})
+				continue
+			}
+
+			if fieldType.Tag.Get(d.config.TagName) == "" && d.config.IgnoreUntaggedFields {
+				continue
+			}
+
+			if tagAliases(tagParts[1:]) != nil || tagKeyBy(tagParts[1:]) != "" || hasTagOption(tagParts[1:], "tuple") {
+				return nil
+			}
+
+			fieldName := fieldType.Name
+			if tagParts[0] != "" {
+				fieldName = tagParts[0]
+			}
+			if d.tagKeyPath(fieldName) != nil {
+				// Key paths are looked up through the nested maps.
+				return nil
+			}
+
+			var candidates []int
+			exact, ok := keys[fieldName]
+			if ok {
+				candidates = append(candidates, exact)
+			}
+			for i, s := range plan.src {
+				if (!ok || i != exact) && d.config.MatchName(s.key, fieldName) {
+					candidates = append(candidates, i)
+				}
+			}
+
+			plan.dst = append(plan.dst, structPlanDst{
+				index:      fieldIndex,
+				name:       fieldName,
+				candidates: candidates,
+			})
+		}
+	}
+
+	return plan
+}
+
+// planStructSource collects the keys of typ the way decodeMapFromStruct
+// would. It returns false if the keys depend on the values of the fields.
+func (d *Decoder) planStructSource(typ reflect.Type, index []int, keys map[string]int, plan *structPlan) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagValue := f.Tag.Get(d.config.TagName)
+		if tagValue == "" && d.config.IgnoreUntaggedFields {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		squash := d.config.Squash && f.Type.Kind() == reflect.Struct && f.Anonymous
+		keyName := f.Name
+		var omitEmpty, omitZero bool
+
+		if index := strings.Index(tagValue, ","); index != -1 {
+			if tagValue[:index] == "-" {
+				continue
+			}
+
+			options := tagValue[index+1:]
+			omitEmpty = strings.Contains(options, "omitempty")
+			omitZero = strings.Contains(options, "omitzero")
+			squash = squash || strings.Contains(options, d.config.SquashTagOption)
+			if squash && f.Type.Kind() != reflect.Struct {
+				return false
+			}
+			if !squash && strings.Contains(options, "remain") {
+				return false
+			}
+
+			if keyNameTagValue := tagValue[:index]; keyNameTagValue != "" {
+				keyName = keyNameTagValue
+			}
+		} else if len(tagValue) > 0 {
+			if tagValue == "-" {
+				continue
+			}
+			keyName = tagValue
+		}
+
+		if squash {
+			// Squashed structs go through decode on their way into the
+			// map, which decode hooks and metadata would observe.
+			if omitEmpty || omitZero || d.cachedDecodeHook != nil || d.config.Metadata != nil {
+				return false
+			}
+
+			// Prefixed keys are only known once the struct is in a map.
+			if tagPrefix(strings.Split(tagValue, ",")[1:]) != "" {
+				return false
+			}
+
+			if !d.planStructSource(f.Type, fieldIndex, keys, plan) {
+				return false
+			}
+			continue
+		}
+
+		// A later field would overwrite the key, unless it is omitted.
+		if _, ok := keys[keyName]; ok {
+			return false
+		}
+
+		// Key paths build nested maps, keyed slices and maps are
+		// converted into each other, and tuples are turned into slices.
+		if d.tagKeyPath(keyName) != nil || tagKeyBy(strings.Split(tagValue, ",")[1:]) != "" {
+			return false
+		}
+		fieldType := f.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if hasTagOption(strings.Split(tagValue, ",")[1:], "tuple") || d.isTupleStruct(fieldType) {
+			return false
+		}
+
+		keys[keyName] = len(plan.src)
+		plan.src = append(plan.src, structPlanSrc{
+			index:     fieldIndex,
+			key:       keyName,
+			omitEmpty: omitEmpty,
+			omitZero:  omitZero,
+		})
+	}
+
+	return true
+}
+
+// decodeStructFromStruct decodes dataVal into val following plan. It
+// behaves exactly like decoding dataVal into a map with decodeMapFromStruct
+// and that map into val with decodeStructFromMap.
+func (d *Decoder) decodeStructFromStruct(name string, plan *structPlan, dataVal, val reflect.Value) error {
+	// Resolve the source values, leaving out the ones that would have been
+	// omitted from the map.
+	present := make([]reflect.Value, len(plan.src))
+	for i, s := range plan.src {
+		v := dereferencePtrToStructIfNeeded(dataVal.FieldByIndex(s.index), d.config.TagName)
+		if s.omitEmpty && isEmptyValue(v) {
+			continue
+		}
+		if s.omitZero && v.IsZero() {
+			continue
+		}
+		present[i] = v
+	}
+
+	used := make([]bool, len(plan.src))
+
+	var errs []error
+	var unset []string
+
+	for _, f := range plan.dst {
+		fieldValue := val.FieldByIndex(f.index)
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Elem().Kind() == reflect.Struct {
+			// Handle embedded struct pointers as embedded structs.
+			fieldValue = fieldValue.Elem()
+		}
+
+		src := -1
+		for _, c := range f.candidates {
+			if present[c].IsValid() {
+				src = c
+				break
+			}
+		}
+
+		if src == -1 {
+			if !(d.config.AllowUnsetPointer && fieldValue.Kind() == reflect.Ptr) {
+				unset = append(unset, f.name)
+			}
+			continue
+		}
+
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		used[src] = true
+
+		fieldName := f.name
+		if name != "" {
+			fieldName = name + "." + fieldName
+		}
+
+		input, err := d.structFieldInput(plan.src[src].key, present[src], fieldValue)
+		if err == nil {
+			err = d.decode(fieldName, input, fieldValue)
+		}
+		if err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				return d.joinErrors(errs)
+			}
+		}
+	}
+
+	var unused []string
+	for i, s := range plan.src {
+		if present[i].IsValid() && !used[i] {
+			unused = append(unused, s.key)
+		}
+	}
+	sort.Strings(unused)
+	unused, ignored := d.ignoreUnusedKeys(name, unused)
+
+	sort.Strings(unset)
+	unset = dedupeSorted(unset)
+
+	if d.config.ErrorUnused && len(unused) > 0 {
+		var stop bool
+		errs, stop = d.addError(errs, unusedKeysError(name, unused, plan.fieldNames(val.Type())))
+		if stop {
+			return d.joinErrors(errs)
+		}
+	}
+
+	if d.config.ErrorUnset && len(unset) > 0 {
+		errs, _ = d.addError(errs, newDecodeError(
+			name,
+			fmt.Errorf("has unset fields: %s", strings.Join(unset, ", ")),
+		))
+	}
+
+	if err := d.joinErrors(errs); err != nil {
+		return err
+	}
+
+	// Add the unused keys to the list of unused keys if we're tracking metadata
+	if d.config.Metadata != nil {
+		suggestions := suggestFieldNames(unused, plan.fieldNames(val.Type()))
+		for _, key := range unused {
+			fieldSuggestions := suggestions[key]
+			if name != "" {
+				key = name + "." + key
+			}
+
+			d.config.Metadata.Unused = append(d.config.Metadata.Unused, key)
+			if len(fieldSuggestions) > 0 {
+				d.config.Metadata.Suggestions[key] = fieldSuggestions
+			}
+		}
+		d.recordIgnoredKeys(name, ignored)
+		for _, key := range unset {
+			if name != "" {
+				key = name + "." + key
+			}
+
+			d.config.Metadata.Unset = append(d.config.Metadata.Unset, key)
+		}
+	}
+
+	return nil
+}
+
+// fieldNames returns the keys the exported fields of dst, the target type
+// of the plan, are decoded from.
+func (p *structPlan) fieldNames(dst reflect.Type) []string {
+	names := make([]string, 0, len(p.dst))
+	for _, f := range p.dst {
+		if dst.FieldByIndex(f.index).PkgPath == "" && f.name != "-" {
+			names = append(names, f.name)
+		}
+	}
+
+	return names
+}
+
+// structFieldInput returns the value of a source field as it would have
+// appeared in the map built by decodeMapFromStruct. Nested structs are
+// passed on as they are when they are decoded into a struct of another
+// type, which goes through a plan of its own. Otherwise converting them to
+// a map is observable: a struct of the same type would be assigned as a
+// whole rather than field by field, decode hooks and metadata see the
+// conversion, and interface targets receive the map itself.
+func (d *Decoder) structFieldInput(keyName string, v reflect.Value, target reflect.Value) (interface{}, error) {
+	if v.Kind() != reflect.Struct {
+		return v.Interface(), nil
+	}
+
+	targetType := target.Type()
+	for targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+	if targetType.Kind() == reflect.Struct && targetType != v.Type() &&
+		d.cachedDecodeHook == nil && d.config.Metadata == nil {
+		return v.Interface(), nil
+	}
+
+	x := reflect.New(v.Type())
+	x.Elem().Set(v)
+
+	vMap := reflect.MakeMap(reflect.TypeOf((map[string]interface{})(nil)))
+	addrVal := reflect.New(vMap.Type())
+	reflect.Indirect(addrVal).Set(vMap)
+
+	if err := d.decode(keyName, x.Interface(), reflect.Indirect(addrVal)); err != nil {
+		return nil, err
+	}
+
+	return reflect.Indirect(addrVal).Interface(), nil
+}
+
+// dedupeSorted removes consecutive duplicates from a sorted slice.
+func dedupeSorted(s []string) []string {
+	if len(s) < 2 {
+		return s
+	}
+
+	result := s[:1]
+	for _, v := range s[1:] {
+		if v != result[len(result)-1] {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
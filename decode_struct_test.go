@@ -0,0 +1,286 @@
+package mapstructure
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStructFromStruct_MatchesMapPath(t *testing.T) {
+	t.Parallel()
+
+	type Address struct {
+		Street string `mapstructure:"street"`
+		City   string
+	}
+
+	type Common struct {
+		ID   int
+		Kind string `mapstructure:"kind"`
+	}
+
+	type Source struct {
+		Common   `mapstructure:",squash"`
+		Name     string                 `mapstructure:"name"`
+		Nick     string                 `mapstructure:"nick,omitempty"`
+		Age      int                    `mapstructure:",omitzero"`
+		Address  Address                `mapstructure:"address"`
+		Home     *Address               `mapstructure:"home"`
+		Tags     []string               `mapstructure:"tags"`
+		Extra    map[string]interface{} `mapstructure:"extra"`
+		Secret   string                 `mapstructure:"-"`
+		Unused   string                 `mapstructure:"unused"`
+		internal string
+	}
+
+	type Target struct {
+		ID      int64
+		Kind    string
+		Name    string `mapstructure:"NAME"`
+		Nick    *string
+		Age     uint
+		Address map[string]interface{} `mapstructure:"address"`
+		Home    struct {
+			Street string `mapstructure:"street"`
+		} `mapstructure:"home"`
+		Tags    []string
+		Extra   interface{}
+		Missing string
+		Secret  string
+	}
+
+	type Flat struct {
+		Name    string
+		Address interface{}
+	}
+
+	sources := []Source{
+		{},
+		{
+			Common:   Common{ID: 1, Kind: "person"},
+			Name:     "alice",
+			Nick:     "al",
+			Age:      30,
+			Address:  Address{Street: "Main", City: "Springfield"},
+			Home:     &Address{Street: "Elm"},
+			Tags:     []string{"a"},
+			Extra:    map[string]interface{}{"foo": "bar"},
+			Secret:   "hidden",
+			Unused:   "yes",
+			internal: "no",
+		},
+	}
+
+	hook := func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if s, ok := data.(string); ok {
+			return strings.ToUpper(s), nil
+		}
+		return data, nil
+	}
+
+	configs := map[string]func() *DecoderConfig{
+		"default":  func() *DecoderConfig { return &DecoderConfig{} },
+		"unused":   func() *DecoderConfig { return &DecoderConfig{ErrorUnused: true} },
+		"unset":    func() *DecoderConfig { return &DecoderConfig{ErrorUnset: true, AllowUnsetPointer: true} },
+		"hook":     func() *DecoderConfig { return &DecoderConfig{DecodeHook: hook} },
+		"metadata": func() *DecoderConfig { return &DecoderConfig{Metadata: &Metadata{}} },
+		"weak":     func() *DecoderConfig { return &DecoderConfig{WeaklyTypedInput: true, ZeroFields: true} },
+		"untagged": func() *DecoderConfig { return &DecoderConfig{IgnoreUntaggedFields: true} },
+	}
+
+	targets := []func() interface{}{
+		func() interface{} { return new(Target) },
+		func() interface{} { return new(Flat) },
+	}
+
+	for configName, newConfig := range configs {
+		for i, source := range sources {
+			for _, newTarget := range targets {
+				direct, directConfig := newTarget(), newConfig()
+				directConfig.Result = direct
+				directDecoder, err := NewDecoder(directConfig)
+				if err != nil {
+					t.Fatalf("err: %s", err)
+				}
+
+				dataVal := reflect.ValueOf(source)
+				val := reflect.ValueOf(direct).Elem()
+				var directErr error
+				if plan := directDecoder.structPlan(dataVal.Type(), val.Type()); plan != nil {
+					directErr = directDecoder.decodeStructFromStruct("", plan, dataVal, val)
+				} else if configName != "hook" && configName != "metadata" {
+					t.Fatalf("%s: expected a plan for %s", configName, val.Type())
+				} else {
+					directErr = directDecoder.decodeStructViaMap("", dataVal, val)
+				}
+
+				viaMap, viaMapConfig := newTarget(), newConfig()
+				viaMapConfig.Result = viaMap
+				viaMapDecoder, err := NewDecoder(viaMapConfig)
+				if err != nil {
+					t.Fatalf("err: %s", err)
+				}
+				viaMapErr := viaMapDecoder.decodeStructViaMap("", dataVal, reflect.ValueOf(viaMap).Elem())
+
+				if (directErr == nil) != (viaMapErr == nil) || (directErr != nil && directErr.Error() != viaMapErr.Error()) {
+					t.Fatalf("%s/%d: errors differ:\ndirect: %v\nvia map: %v", configName, i, directErr, viaMapErr)
+				}
+
+				if !reflect.DeepEqual(direct, viaMap) {
+					t.Fatalf("%s/%d: results differ:\ndirect: %#v\nvia map: %#v", configName, i, direct, viaMap)
+				}
+
+				if md := directConfig.Metadata; md != nil {
+					for _, m := range []*Metadata{md, viaMapConfig.Metadata} {
+						sort.Strings(m.Keys)
+						sort.Strings(m.Unused)
+						sort.Strings(m.Unset)
+					}
+					if !reflect.DeepEqual(md, viaMapConfig.Metadata) {
+						t.Fatalf("%s/%d: metadata differs:\ndirect: %#v\nvia map: %#v", configName, i, md, viaMapConfig.Metadata)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeStructFromStruct_Fallback(t *testing.T) {
+	t.Parallel()
+
+	type Base struct {
+		Name string
+	}
+
+	type WithRemain struct {
+		Name  string
+		Other map[string]interface{} `mapstructure:",remain"`
+	}
+
+	type WithPointerSquash struct {
+		*Base `mapstructure:",squash"`
+	}
+
+	type WithAlias struct {
+		Name string `mapstructure:"name,alias=title"`
+	}
+
+	type Duplicate struct {
+		Base  `mapstructure:",squash"`
+		Other string `mapstructure:"Name"`
+	}
+
+	tests := []struct {
+		name string
+		src  interface{}
+		dst  interface{}
+	}{
+		{"remain target", Base{}, WithRemain{}},
+		{"remain source", WithRemain{}, Base{}},
+		{"pointer squash", Base{}, WithPointerSquash{}},
+		{"alias", Base{}, WithAlias{}},
+		{"duplicate source keys", Duplicate{}, Base{}},
+	}
+
+	decoder, err := NewDecoder(&DecoderConfig{Result: &map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, tc := range tests {
+		if plan := decoder.structPlan(reflect.TypeOf(tc.src), reflect.TypeOf(tc.dst)); plan != nil {
+			t.Errorf("%s: expected no plan", tc.name)
+		}
+	}
+}
+
+func TestDecodeStructFromStruct_NestedExistingValues(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		A string
+		B string
+	}
+
+	type Source struct {
+		In struct {
+			B string
+		}
+	}
+
+	type Nested struct {
+		In Inner
+	}
+
+	var src Source
+	src.In.B = "x"
+
+	result := Nested{In: Inner{A: "keep", B: "old"}}
+	if err := Decode(src, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Nested{In: Inner{A: "keep", B: "x"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// A nested struct of the target's type is decoded field by field too,
+	// so the fields omitted from the source are left alone.
+	type Partial struct {
+		A string `mapstructure:",omitempty"`
+		B string
+	}
+
+	type PartialSource struct {
+		In Partial
+	}
+
+	type PartialTarget struct {
+		In Partial
+	}
+
+	partial := PartialTarget{In: Partial{A: "keep", B: "old"}}
+	if err := Decode(PartialSource{In: Partial{B: "x"}}, &partial); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedPartial := PartialTarget{In: Partial{A: "keep", B: "x"}}
+	if !reflect.DeepEqual(partial, expectedPartial) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expectedPartial, partial)
+	}
+}
+
+func TestDecodeStructFromStruct_NestedUnset(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		A string `mapstructure:",omitempty"`
+		B string
+	}
+
+	type Source struct {
+		In Inner
+	}
+
+	type Target struct {
+		In Inner
+	}
+
+	var result Target
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnset: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(Source{In: Inner{B: "x"}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := "decoding failed due to the following error(s):\n\n'In' has unset fields: A"
+	if err.Error() != expected {
+		t.Fatalf("expected error:\n%s\ngot:\n%s", expected, err)
+	}
+}
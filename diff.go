@@ -0,0 +1,400 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType is the kind of a Change reported by Diff.
+type ChangeType int
+
+const (
+	// ChangeAdded means the path is only present in the second value.
+	ChangeAdded ChangeType = iota
+
+	// ChangeRemoved means the path is only present in the first value.
+	ChangeRemoved
+
+	// ChangeModified means the path is present in both values but its
+	// value differs.
+	ChangeModified
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// Change is a single difference found by Diff.
+//
+// Path uses the names a struct would have as keys of a map, joined the same
+// way decoding errors are: "servers[1].tls.cert". From and To hold the
+// values at the path, with structs converted to maps. From is nil for added
+// paths and To is nil for removed ones.
+type Change struct {
+	Type ChangeType
+	Path string
+	From interface{}
+	To   interface{}
+}
+
+// String formats the change for logs, e.g. "modified servers[1].port: 80 -> 8080".
+func (c Change) String() string {
+	var b strings.Builder
+	b.WriteString(c.Type.String())
+	b.WriteString(" ")
+	b.WriteString(c.Path)
+
+	switch c.Type {
+	case ChangeAdded:
+		fmt.Fprintf(&b, ": %v", c.To)
+	case ChangeRemoved:
+		fmt.Fprintf(&b, ": %v", c.From)
+	default:
+		fmt.Fprintf(&b, ": %v -> %v", c.From, c.To)
+	}
+
+	return b.String()
+}
+
+// DiffConfig is the configuration used by Diff.
+type DiffConfig struct {
+	// IdentityKeys matches the elements of slices and maps by the value of
+	// a key instead of their position or map key. It maps the path of the
+	// collection, without any indexes, to the name of the key, for example
+	// {"servers": "name"} or {"clusters.nodes": "id"}. The elements are
+	// reported as "servers[web]" instead of "servers[1]". Elements without
+	// the key are matched by position.
+	IdentityKeys map[string]string
+
+	// The tag name that mapstructure reads for field names. This
+	// defaults to "mapstructure".
+	TagName string
+
+	// Squash will squash embedded structs. See DecoderConfig.
+	Squash bool
+
+	// IgnoreUntaggedFields ignores all struct fields without explicit
+	// TagName. See DecoderConfig.
+	IgnoreUntaggedFields bool
+}
+
+// Diff compares a and b, usually two instances of the same struct, and
+// returns the paths that were added, removed or modified between them.
+// Changes are reported in a stable order: fields and map keys sorted by
+// name and slice elements by index.
+//
+// Structs are compared field by field after converting them the way
+// decoding a struct into a map does, so "squash", "remain", "-" and the
+// configured TagName are taken into account. Structs without exported
+// fields, such as time.Time, are compared as single values.
+func Diff(a, b any, config *DiffConfig) []Change {
+	if config == nil {
+		config = &DiffConfig{}
+	}
+
+	differ := &differ{identityKeys: config.IdentityKeys}
+
+	var result map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{
+		EncodeHook: func(from reflect.Value) (interface{}, error) {
+			v := differ.normalize(from)
+
+			// Structs compared as single values would be turned into
+			// maps, so they are passed on behind a pointer instead.
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Struct {
+				ptr := reflect.New(rv.Type())
+				ptr.Elem().Set(rv)
+				return ptr.Interface(), nil
+			}
+
+			return v, nil
+		},
+		TagName:              config.TagName,
+		Squash:               config.Squash,
+		IgnoreUntaggedFields: config.IgnoreUntaggedFields,
+		Result:               &result,
+	})
+	if err != nil {
+		return nil
+	}
+	differ.decoder = decoder
+
+	from := differ.normalize(reflect.ValueOf(a))
+	to := differ.normalize(reflect.ValueOf(b))
+	differ.diff("", "", from, to)
+
+	return differ.changes
+}
+
+// diffStruct is a struct converted to a map by differ.normalize. Its keys
+// are joined to paths with a dot, unlike the keys of maps.
+type diffStruct map[string]interface{}
+
+// differ holds the state of a single call to Diff.
+type differ struct {
+	decoder      *Decoder
+	identityKeys map[string]string
+	changes      []Change
+}
+
+// normalize converts v into a tree of diffStruct, map[string]interface{},
+// []interface{} and plain values that can be compared with diff. Values
+// that were already normalized are returned unchanged.
+func (d *differ) normalize(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == reflect.TypeOf(diffStruct(nil)) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return d.normalize(v.Elem())
+
+	case reflect.Struct:
+		if !isStructTypeConvertibleToMap(v.Type(), false, "") {
+			return v.Interface()
+		}
+
+		// The encode hook normalizes the fields, except for the values of
+		// remain fields, which are copied into the map as they are.
+		m := make(map[string]interface{})
+		if err := d.decoder.decode("", v.Interface(), reflect.ValueOf(&m).Elem()); err != nil {
+			return v.Interface()
+		}
+
+		result := make(diffStruct, len(m))
+		for k, field := range m {
+			result[k] = d.normalize(reflect.ValueOf(field))
+		}
+		return result
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		m := make(map[string]interface{}, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			m[fmt.Sprint(it.Key().Interface())] = d.normalize(it.Value())
+		}
+		return m
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+
+		s := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s[i] = d.normalize(v.Index(i))
+		}
+		return s
+	}
+
+	return v.Interface()
+}
+
+// add records a change. Normalized structs are turned back into plain
+// maps, so that users can inspect the values.
+func (d *differ) add(typ ChangeType, path string, from, to interface{}) {
+	d.changes = append(d.changes, Change{
+		Type: typ,
+		Path: path,
+		From: plainDiffValue(from),
+		To:   plainDiffValue(to),
+	})
+}
+
+// diff records the changes between from and to. The pattern is the path
+// without indexes, which is used to look up identity keys.
+func (d *differ) diff(path, pattern string, from, to interface{}) {
+	switch {
+	case from == nil && to == nil:
+		return
+	case from == nil:
+		d.add(ChangeAdded, path, nil, to)
+		return
+	case to == nil:
+		d.add(ChangeRemoved, path, from, nil)
+		return
+	}
+
+	if key, ok := d.identityKeys[pattern]; ok {
+		fromElems, fromOk := identityElements(from, key)
+		toElems, toOk := identityElements(to, key)
+		if fromOk && toOk {
+			d.diffMaps(path, pattern, fromElems, toElems, true)
+			return
+		}
+	}
+
+	switch fromVal := from.(type) {
+	case diffStruct:
+		if toVal, ok := to.(diffStruct); ok {
+			d.diffMaps(path, pattern, fromVal, toVal, false)
+			return
+		}
+
+	case map[string]interface{}:
+		if toVal, ok := to.(map[string]interface{}); ok {
+			d.diffMaps(path, pattern, fromVal, toVal, true)
+			return
+		}
+
+	case []interface{}:
+		if toVal, ok := to.([]interface{}); ok {
+			d.diffSlices(path, pattern, fromVal, toVal)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		d.add(ChangeModified, path, from, to)
+	}
+}
+
+// diffMaps compares two maps key by key. The keys of structs are joined to
+// the path with a dot, while the keys of maps and identities use brackets.
+func (d *differ) diffMaps(path, pattern string, from, to map[string]interface{}, brackets bool) {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fromVal, fromOk := from[k]
+		toVal, toOk := to[k]
+
+		keyPath, keyPattern := path+"["+k+"]", pattern
+		if !brackets {
+			keyPath, keyPattern = joinDiffPath(path, k), joinDiffPath(pattern, k)
+		}
+
+		switch {
+		case !toOk:
+			d.add(ChangeRemoved, keyPath, fromVal, nil)
+		case !fromOk:
+			d.add(ChangeAdded, keyPath, nil, toVal)
+		default:
+			d.diff(keyPath, keyPattern, fromVal, toVal)
+		}
+	}
+}
+
+// diffSlices compares two slices element by element.
+func (d *differ) diffSlices(path, pattern string, from, to []interface{}) {
+	for i := 0; i < len(from) || i < len(to); i++ {
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+
+		switch {
+		case i >= len(to):
+			d.add(ChangeRemoved, elemPath, from[i], nil)
+		case i >= len(from):
+			d.add(ChangeAdded, elemPath, nil, to[i])
+		default:
+			d.diff(elemPath, pattern, from[i], to[i])
+		}
+	}
+}
+
+// identityElements indexes the elements of a normalized slice or map by the
+// value of key. It returns false if an element doesn't have the key or two
+// elements share the same identity.
+func identityElements(v interface{}, key string) (map[string]interface{}, bool) {
+	var elems []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		elems = v
+	case map[string]interface{}:
+		for _, elem := range v {
+			elems = append(elems, elem)
+		}
+	default:
+		return nil, false
+	}
+
+	result := make(map[string]interface{}, len(elems))
+	for _, elem := range elems {
+		m, ok := elem.(diffStruct)
+		if !ok {
+			return nil, false
+		}
+
+		id, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+
+		k := fmt.Sprint(id)
+		if _, ok := result[k]; ok {
+			return nil, false
+		}
+		result[k] = elem
+	}
+
+	return result, true
+}
+
+func joinDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// plainDiffValue replaces the diffStructs in a normalized value with
+// map[string]interface{}.
+func plainDiffValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case diffStruct:
+		m := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			m[k] = plainDiffValue(elem)
+		}
+		return m
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			m[k] = plainDiffValue(elem)
+		}
+		return m
+
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, elem := range v {
+			s[i] = plainDiffValue(elem)
+		}
+		return s
+	}
+
+	return v
+}
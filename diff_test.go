@@ -0,0 +1,128 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type diffTLS struct {
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+type diffServer struct {
+	Name string   `mapstructure:"name"`
+	Port int      `mapstructure:"port"`
+	TLS  *diffTLS `mapstructure:"tls"`
+}
+
+type DiffCommon struct {
+	Region string `mapstructure:"region"`
+}
+
+type diffConfig struct {
+	DiffCommon `mapstructure:",squash"`
+
+	Servers []diffServer           `mapstructure:"servers"`
+	Labels  map[string]string      `mapstructure:"labels"`
+	Started time.Time              `mapstructure:"started"`
+	Secret  string                 `mapstructure:"-"`
+	Extra   map[string]interface{} `mapstructure:",remain"`
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	a := diffConfig{
+		DiffCommon: DiffCommon{Region: "eu"},
+		Servers: []diffServer{
+			{Name: "api", Port: 80},
+			{Name: "web", Port: 443, TLS: &diffTLS{Cert: "a.pem", Key: "a.key"}},
+		},
+		Labels:  map[string]string{"env": "prod", "team": "core"},
+		Started: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Secret:  "a",
+		Extra:   map[string]interface{}{"debug": false},
+	}
+
+	b := diffConfig{
+		DiffCommon: DiffCommon{Region: "us"},
+		Servers: []diffServer{
+			{Name: "api", Port: 80},
+			{Name: "web", Port: 443, TLS: &diffTLS{Cert: "b.pem", Key: "a.key"}},
+			{Name: "admin", Port: 8080},
+		},
+		Labels:  map[string]string{"env": "prod", "owner": "ops"},
+		Started: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Secret:  "b",
+		Extra:   map[string]interface{}{"debug": true},
+	}
+
+	expected := []Change{
+		{Type: ChangeModified, Path: "debug", From: false, To: true},
+		{Type: ChangeAdded, Path: "labels[owner]", To: "ops"},
+		{Type: ChangeRemoved, Path: "labels[team]", From: "core"},
+		{Type: ChangeModified, Path: "region", From: "eu", To: "us"},
+		{Type: ChangeModified, Path: "servers[1].tls.cert", From: "a.pem", To: "b.pem"},
+		{
+			Type: ChangeAdded,
+			Path: "servers[2]",
+			To:   map[string]interface{}{"name": "admin", "port": 8080, "tls": nil},
+		},
+		{Type: ChangeModified, Path: "started", From: a.Started, To: b.Started},
+	}
+
+	actual := Diff(a, &b, nil)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Diff() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	if actual := Diff(a, a, nil); len(actual) != 0 {
+		t.Fatalf("expected no changes, got %#v", actual)
+	}
+}
+
+func TestDiff_IdentityKeys(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Servers []diffServer          `mapstructure:"servers"`
+		ByHost  map[string]diffServer `mapstructure:"by_host"`
+	}
+
+	a := Config{
+		Servers: []diffServer{{Name: "api", Port: 80}, {Name: "web", Port: 443}},
+		ByHost:  map[string]diffServer{"h1": {Name: "api", Port: 80}},
+	}
+	b := Config{
+		Servers: []diffServer{{Name: "web", Port: 8443}, {Name: "admin", Port: 9000}},
+		ByHost:  map[string]diffServer{"h2": {Name: "api", Port: 81}},
+	}
+
+	expected := []Change{
+		{Type: ChangeModified, Path: "by_host[api].port", From: 80, To: 81},
+		{
+			Type: ChangeAdded,
+			Path: "servers[admin]",
+			To:   map[string]interface{}{"name": "admin", "port": 9000, "tls": nil},
+		},
+		{
+			Type: ChangeRemoved,
+			Path: "servers[api]",
+			From: map[string]interface{}{"name": "api", "port": 80, "tls": nil},
+		},
+		{Type: ChangeModified, Path: "servers[web].port", From: 443, To: 8443},
+	}
+
+	actual := Diff(a, b, &DiffConfig{
+		IdentityKeys: map[string]string{"servers": "name", "by_host": "name"},
+	})
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Diff() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	if s := actual[0].String(); s != "modified by_host[api].port: 80 -> 81" {
+		t.Fatalf("unexpected string: %s", s)
+	}
+}
@@ -0,0 +1,195 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// EncodeHookFunc is the callback function that can be used to transform
+// field values when a struct is encoded into a map. See "EncodeHook" in the
+// DecoderConfig struct.
+//
+// The returned value is put into the map instead of the field value. Hooks
+// that don't handle the value should return it unchanged.
+type EncodeHookFunc func(from reflect.Value) (interface{}, error)
+
+// EncoderConfig is the configuration used by Encode.
+type EncoderConfig struct {
+	// EncodeHook, if set, will be called for every field value before it
+	// is put into the map. See DecoderConfig.
+	EncodeHook EncodeHookFunc
+
+	// Redaction, if set, redacts the values of fields tagged with the
+	// "sensitive" option.
+	Redaction *Redaction
+
+	// The tag name that mapstructure reads for field names. This
+	// defaults to "mapstructure".
+	TagName string
+
+	// Squash will squash embedded structs. See DecoderConfig.
+	Squash bool
+
+	// IgnoreUntaggedFields ignores all struct fields without explicit
+	// TagName. See DecoderConfig.
+	IgnoreUntaggedFields bool
+}
+
+// Encode converts a struct, or a pointer to one, into a map. It is the same
+// as decoding the struct into a map[string]interface{}, using the tags and
+// hooks of the configuration.
+func Encode(input interface{}, config *EncoderConfig) (map[string]interface{}, error) {
+	if config == nil {
+		config = &EncoderConfig{}
+	}
+
+	var result map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{
+		EncodeHook:           config.EncodeHook,
+		Redaction:            config.Redaction,
+		TagName:              config.TagName,
+		Squash:               config.Squash,
+		IgnoreUntaggedFields: config.IgnoreUntaggedFields,
+		Result:               &result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ComposeEncodeHookFunc creates a single EncodeHookFunc that automatically
+// composes multiple EncodeHookFuncs.
+//
+// The composed funcs are called in order, with the result of the previous
+// transformation.
+func ComposeEncodeHookFunc(fs ...EncodeHookFunc) EncodeHookFunc {
+	return func(from reflect.Value) (interface{}, error) {
+		var err error
+		data := from.Interface()
+
+		newFrom := from
+		for _, f := range fs {
+			data, err = f(newFrom)
+			if err != nil {
+				return nil, err
+			}
+			newFrom = reflect.ValueOf(data)
+			if !newFrom.IsValid() {
+				break
+			}
+		}
+
+		return data, nil
+	}
+}
+
+// encodeValue passes v to the EncodeHook. The elements of slices, arrays
+// and maps the hook leaves as they are are passed to it as well, so that
+// []time.Duration is encoded like time.Duration. Containers keep their type
+// unless the hook changes the type of an element, in which case they become
+// []interface{} or map[K]interface{}. An invalid value is returned if the
+// hook returns nil.
+func (d *Decoder) encodeValue(name string, v reflect.Value) (reflect.Value, error) {
+	encoded, err := d.config.EncodeHook(v)
+	if err != nil {
+		return reflect.Value{}, newDecodeError(name, err)
+	}
+	if encoded == nil {
+		return reflect.Value{}, nil
+	}
+
+	result := reflect.ValueOf(encoded)
+	if result.Type() != v.Type() {
+		return result, nil
+	}
+
+	interfaceType := reflect.TypeOf((*interface{})(nil)).Elem()
+
+	switch result.Kind() {
+	case reflect.Slice, reflect.Array:
+		if result.Kind() == reflect.Slice && result.IsNil() {
+			return result, nil
+		}
+
+		elems := make([]reflect.Value, result.Len())
+		keepType := true
+		for i := range elems {
+			elem, err := d.encodeElem(name+"["+strconv.Itoa(i)+"]", result.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			elems[i] = elem
+			keepType = keepType && elem.IsValid() && elem.Type().AssignableTo(result.Type().Elem())
+		}
+
+		var out reflect.Value
+		switch {
+		case !keepType:
+			out = reflect.MakeSlice(reflect.SliceOf(interfaceType), len(elems), len(elems))
+		case result.Kind() == reflect.Slice:
+			out = reflect.MakeSlice(result.Type(), len(elems), len(elems))
+		default:
+			out = reflect.New(result.Type()).Elem()
+		}
+		for i, elem := range elems {
+			if elem.IsValid() {
+				out.Index(i).Set(elem)
+			}
+		}
+		return out, nil
+
+	case reflect.Map:
+		if result.IsNil() {
+			return result, nil
+		}
+
+		keys := make([]reflect.Value, 0, result.Len())
+		elems := make([]reflect.Value, 0, result.Len())
+		keepType := true
+		for it := result.MapRange(); it.Next(); {
+			elem, err := d.encodeElem(name+"["+fmt.Sprint(it.Key().Interface())+"]", it.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			keys = append(keys, it.Key())
+			elems = append(elems, elem)
+			keepType = keepType && elem.IsValid() && elem.Type().AssignableTo(result.Type().Elem())
+		}
+
+		mapType := result.Type()
+		if !keepType {
+			mapType = reflect.MapOf(mapType.Key(), interfaceType)
+		}
+		out := reflect.MakeMapWithSize(mapType, len(keys))
+		for i, key := range keys {
+			elem := elems[i]
+			if !elem.IsValid() {
+				elem = reflect.Zero(mapType.Elem())
+			}
+			out.SetMapIndex(key, elem)
+		}
+		return out, nil
+	}
+
+	return result, nil
+}
+
+// encodeElem encodes an element of a slice, array or map. Elements held by
+// interfaces are passed to the hook by their concrete value.
+func (d *Decoder) encodeElem(name string, v reflect.Value) (reflect.Value, error) {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, nil
+		}
+		v = v.Elem()
+	}
+
+	return d.encodeValue(name, v)
+}
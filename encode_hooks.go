@@ -0,0 +1,281 @@
+package mapstructure
+
+import (
+	"encoding"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typedEncodeHook returns an EncodeHookFunc that converts values of type T,
+// or non-nil pointers to them, using fn. Other values are returned as is.
+func typedEncodeHook[T any](fn func(T) (interface{}, error)) EncodeHookFunc {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	return func(from reflect.Value) (interface{}, error) {
+		v := from
+		if v.Kind() == reflect.Ptr && v.Type().Elem() == typ {
+			if v.IsNil() {
+				return from.Interface(), nil
+			}
+			v = v.Elem()
+		}
+
+		if v.Type() != typ {
+			return from.Interface(), nil
+		}
+
+		return fn(v.Interface().(T))
+	}
+}
+
+// SliceToStringHookFunc returns an EncodeHookFunc that converts []string
+// to string by joining it with the given sep. It is the counterpart of
+// StringToSliceHookFunc.
+func SliceToStringHookFunc(sep string) EncodeHookFunc {
+	return typedEncodeHook(func(s []string) (interface{}, error) {
+		return strings.Join(s, sep), nil
+	})
+}
+
+// TimeDurationToStringHookFunc returns an EncodeHookFunc that converts
+// time.Duration to string. It is the counterpart of
+// StringToTimeDurationHookFunc.
+func TimeDurationToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(d time.Duration) (interface{}, error) {
+		return d.String(), nil
+	})
+}
+
+// URLToStringHookFunc returns an EncodeHookFunc that converts *url.URL to
+// string. It is the counterpart of StringToURLHookFunc.
+func URLToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(u url.URL) (interface{}, error) {
+		return u.String(), nil
+	})
+}
+
+// IPToStringHookFunc returns an EncodeHookFunc that converts net.IP to
+// string. It is the counterpart of StringToIPHookFunc.
+func IPToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(ip net.IP) (interface{}, error) {
+		return ip.String(), nil
+	})
+}
+
+// IPNetToStringHookFunc returns an EncodeHookFunc that converts net.IPNet
+// to string. It is the counterpart of StringToIPNetHookFunc.
+func IPNetToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(n net.IPNet) (interface{}, error) {
+		return n.String(), nil
+	})
+}
+
+// TimeToStringHookFunc returns an EncodeHookFunc that converts time.Time
+// to string using the given layout. It is the counterpart of
+// StringToTimeHookFunc.
+func TimeToStringHookFunc(layout string) EncodeHookFunc {
+	return typedEncodeHook(func(t time.Time) (interface{}, error) {
+		return t.Format(layout), nil
+	})
+}
+
+// NetIPAddrToStringHookFunc returns an EncodeHookFunc that converts
+// netip.Addr to string. It is the counterpart of StringToNetIPAddrHookFunc.
+func NetIPAddrToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(a netip.Addr) (interface{}, error) {
+		return a.String(), nil
+	})
+}
+
+// NetIPAddrPortToStringHookFunc returns an EncodeHookFunc that converts
+// netip.AddrPort to string. It is the counterpart of
+// StringToNetIPAddrPortHookFunc.
+func NetIPAddrPortToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(a netip.AddrPort) (interface{}, error) {
+		return a.String(), nil
+	})
+}
+
+// NetIPPrefixToStringHookFunc returns an EncodeHookFunc that converts
+// netip.Prefix to string. It is the counterpart of
+// StringToNetIPPrefixHookFunc.
+func NetIPPrefixToStringHookFunc() EncodeHookFunc {
+	return typedEncodeHook(func(p netip.Prefix) (interface{}, error) {
+		return p.String(), nil
+	})
+}
+
+// TextMarshalerEncodeHook returns an EncodeHookFunc that converts
+// values implementing encoding.TextMarshaler to string. It is the
+// counterpart of TextUnmarshallerHookFunc.
+func TextMarshalerEncodeHook() EncodeHookFunc {
+	return func(from reflect.Value) (interface{}, error) {
+		if from.Kind() == reflect.Ptr && from.IsNil() {
+			return from.Interface(), nil
+		}
+
+		marshaler, ok := from.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return from.Interface(), nil
+		}
+
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return string(text), nil
+	}
+}
+
+// BasicTypeToStringHookFunc returns an EncodeHookFunc that converts basic
+// types to string. It is the counterpart of StringToBasicTypeHookFunc.
+// int8, uint8, int16, uint16, int32, uint32, int64, uint64, int, uint, float32, float64, bool, byte, rune, complex64, complex128
+func BasicTypeToStringHookFunc() EncodeHookFunc {
+	return ComposeEncodeHookFunc(
+		Int8ToStringHookFunc(),
+		Uint8ToStringHookFunc(),
+		Int16ToStringHookFunc(),
+		Uint16ToStringHookFunc(),
+		Int32ToStringHookFunc(),
+		Uint32ToStringHookFunc(),
+		Int64ToStringHookFunc(),
+		Uint64ToStringHookFunc(),
+		IntToStringHookFunc(),
+		UintToStringHookFunc(),
+		Float32ToStringHookFunc(),
+		Float64ToStringHookFunc(),
+		BoolToStringHookFunc(),
+		Complex64ToStringHookFunc(),
+		Complex128ToStringHookFunc(),
+	)
+}
+
+// kindToStringHookFunc returns an EncodeHookFunc that converts values of
+// the given kind to string using format.
+func kindToStringHookFunc(kind reflect.Kind, format func(reflect.Value) string) EncodeHookFunc {
+	return func(from reflect.Value) (interface{}, error) {
+		if from.Kind() != kind {
+			return from.Interface(), nil
+		}
+
+		return format(from), nil
+	}
+}
+
+func formatInt(v reflect.Value) string  { return strconv.FormatInt(v.Int(), 10) }
+func formatUint(v reflect.Value) string { return strconv.FormatUint(v.Uint(), 10) }
+func formatBool(v reflect.Value) string { return strconv.FormatBool(v.Bool()) }
+func formatFloat(bitSize int) func(reflect.Value) string {
+	return func(v reflect.Value) string { return strconv.FormatFloat(v.Float(), 'g', -1, bitSize) }
+}
+func formatComplex(bitSize int) func(reflect.Value) string {
+	return func(v reflect.Value) string { return strconv.FormatComplex(v.Complex(), 'g', -1, bitSize) }
+}
+
+// Int8ToStringHookFunc returns an EncodeHookFunc that converts int8 to
+// string.
+func Int8ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Int8, formatInt)
+}
+
+// Uint8ToStringHookFunc returns an EncodeHookFunc that converts uint8 to
+// string.
+func Uint8ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Uint8, formatUint)
+}
+
+// Int16ToStringHookFunc returns an EncodeHookFunc that converts int16 to
+// string.
+func Int16ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Int16, formatInt)
+}
+
+// Uint16ToStringHookFunc returns an EncodeHookFunc that converts uint16 to
+// string.
+func Uint16ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Uint16, formatUint)
+}
+
+// Int32ToStringHookFunc returns an EncodeHookFunc that converts int32 to
+// string.
+func Int32ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Int32, formatInt)
+}
+
+// Uint32ToStringHookFunc returns an EncodeHookFunc that converts uint32 to
+// string.
+func Uint32ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Uint32, formatUint)
+}
+
+// Int64ToStringHookFunc returns an EncodeHookFunc that converts int64 to
+// string.
+func Int64ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Int64, formatInt)
+}
+
+// Uint64ToStringHookFunc returns an EncodeHookFunc that converts uint64 to
+// string.
+func Uint64ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Uint64, formatUint)
+}
+
+// IntToStringHookFunc returns an EncodeHookFunc that converts int to
+// string.
+func IntToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Int, formatInt)
+}
+
+// UintToStringHookFunc returns an EncodeHookFunc that converts uint to
+// string.
+func UintToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Uint, formatUint)
+}
+
+// Float32ToStringHookFunc returns an EncodeHookFunc that converts float32
+// to string.
+func Float32ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Float32, formatFloat(32))
+}
+
+// Float64ToStringHookFunc returns an EncodeHookFunc that converts float64
+// to string.
+func Float64ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Float64, formatFloat(64))
+}
+
+// BoolToStringHookFunc returns an EncodeHookFunc that converts bool to
+// string.
+func BoolToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Bool, formatBool)
+}
+
+// ByteToStringHookFunc returns an EncodeHookFunc that converts byte to
+// string.
+func ByteToStringHookFunc() EncodeHookFunc {
+	return Uint8ToStringHookFunc()
+}
+
+// RuneToStringHookFunc returns an EncodeHookFunc that converts rune to
+// string.
+func RuneToStringHookFunc() EncodeHookFunc {
+	return Int32ToStringHookFunc()
+}
+
+// Complex64ToStringHookFunc returns an EncodeHookFunc that converts
+// complex64 to string.
+func Complex64ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Complex64, formatComplex(64))
+}
+
+// Complex128ToStringHookFunc returns an EncodeHookFunc that converts
+// complex128 to string.
+func Complex128ToStringHookFunc() EncodeHookFunc {
+	return kindToStringHookFunc(reflect.Complex128, formatComplex(128))
+}
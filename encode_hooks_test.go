@@ -0,0 +1,249 @@
+package mapstructure
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeHookFuncs(t *testing.T) {
+	t.Parallel()
+
+	link, _ := url.Parse("https://example.com/path")
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	moment := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		f      EncodeHookFunc
+		from   interface{}
+		result interface{}
+	}{
+		{"duration", TimeDurationToStringHookFunc(), 5 * time.Second, "5s"},
+		{"duration pointer", TimeDurationToStringHookFunc(), func() *time.Duration { d := time.Minute; return &d }(), "1m0s"},
+		{"duration nil pointer", TimeDurationToStringHookFunc(), (*time.Duration)(nil), (*time.Duration)(nil)},
+		{"duration other type", TimeDurationToStringHookFunc(), 5, 5},
+		{"url", URLToStringHookFunc(), link, "https://example.com/path"},
+		{"ip", IPToStringHookFunc(), net.ParseIP("10.0.0.1"), "10.0.0.1"},
+		{"ipnet", IPNetToStringHookFunc(), ipNet, "10.0.0.0/8"},
+		{"time", TimeToStringHookFunc(time.RFC3339), moment, "2024-05-01T12:30:00Z"},
+		{"netip addr", NetIPAddrToStringHookFunc(), netip.MustParseAddr("::1"), "::1"},
+		{"netip addrport", NetIPAddrPortToStringHookFunc(), netip.MustParseAddrPort("10.0.0.1:80"), "10.0.0.1:80"},
+		{"netip prefix", NetIPPrefixToStringHookFunc(), netip.MustParsePrefix("10.0.0.0/8"), "10.0.0.0/8"},
+		{"slice", SliceToStringHookFunc(","), []string{"a", "b"}, "a,b"},
+		{"int8", Int8ToStringHookFunc(), int8(-8), "-8"},
+		{"uint64", Uint64ToStringHookFunc(), uint64(64), "64"},
+		{"int mismatch", IntToStringHookFunc(), int64(1), int64(1)},
+		{"float32", Float32ToStringHookFunc(), float32(1.5), "1.5"},
+		{"float64", Float64ToStringHookFunc(), 0.1, "0.1"},
+		{"bool", BoolToStringHookFunc(), true, "true"},
+		{"byte", ByteToStringHookFunc(), byte(7), "7"},
+		{"rune", RuneToStringHookFunc(), 'a', "97"},
+		{"complex128", Complex128ToStringHookFunc(), complex(1, 2), "(1+2i)"},
+		{"basic type", BasicTypeToStringHookFunc(), uint16(16), "16"},
+		{"basic type other", BasicTypeToStringHookFunc(), "plain", "plain"},
+		{"text marshaler", TextMarshalerEncodeHook(), netip.MustParseAddr("10.0.0.1"), "10.0.0.1"},
+		{"text marshaler pointer", TextMarshalerEncodeHook(), &moment, "2024-05-01T12:30:00Z"},
+		{"text marshaler nil", TextMarshalerEncodeHook(), (*time.Time)(nil), (*time.Time)(nil)},
+		{"text marshaler other", TextMarshalerEncodeHook(), 1, 1},
+	}
+
+	for _, tc := range cases {
+		actual, err := tc.f(reflect.ValueOf(tc.from))
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(actual, tc.result) {
+			t.Fatalf("%s: expected %#v, got %#v", tc.name, tc.result, actual)
+		}
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Addr   netip.AddrPort `mapstructure:"addr"`
+		Weight float32        `mapstructure:"weight"`
+	}
+
+	type Config struct {
+		Timeout  time.Duration `mapstructure:"timeout"`
+		Started  time.Time     `mapstructure:"started"`
+		Link     *url.URL      `mapstructure:"link"`
+		IP       net.IP        `mapstructure:"ip"`
+		Network  net.IPNet     `mapstructure:"network"`
+		Prefix   netip.Prefix  `mapstructure:"prefix"`
+		Tags     []string      `mapstructure:"tags"`
+		Retries  uint8         `mapstructure:"retries"`
+		Enabled  bool          `mapstructure:"enabled"`
+		Primary  Server        `mapstructure:"primary"`
+		Optional *Server       `mapstructure:"optional,omitempty"`
+	}
+
+	link, _ := url.Parse("https://example.com")
+	_, network, _ := net.ParseCIDR("192.168.0.0/16")
+	input := Config{
+		Timeout: 90 * time.Second,
+		Started: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+		Link:    link,
+		IP:      net.ParseIP("10.0.0.1"),
+		Network: *network,
+		Prefix:  netip.MustParsePrefix("10.0.0.0/8"),
+		Tags:    []string{"a", "b"},
+		Retries: 3,
+		Enabled: true,
+		Primary: Server{Addr: netip.MustParseAddrPort("10.0.0.2:8080"), Weight: 0.5},
+	}
+
+	encoded, err := Encode(&input, &EncoderConfig{
+		EncodeHook: ComposeEncodeHookFunc(
+			TimeDurationToStringHookFunc(),
+			TimeToStringHookFunc(time.RFC3339),
+			URLToStringHookFunc(),
+			IPToStringHookFunc(),
+			IPNetToStringHookFunc(),
+			SliceToStringHookFunc(","),
+			TextMarshalerEncodeHook(),
+			BasicTypeToStringHookFunc(),
+		),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedMap := map[string]interface{}{
+		"timeout": "1m30s",
+		"started": "2024-05-01T12:30:00Z",
+		"link":    "https://example.com",
+		"ip":      "10.0.0.1",
+		"network": "192.168.0.0/16",
+		"prefix":  "10.0.0.0/8",
+		"tags":    "a,b",
+		"retries": "3",
+		"enabled": "true",
+		"primary": map[string]interface{}{
+			"addr":   "10.0.0.2:8080",
+			"weight": "0.5",
+		},
+	}
+	if !reflect.DeepEqual(encoded, expectedMap) {
+		t.Fatalf("Encode() expected: %#v\ngot: %#v", expectedMap, encoded)
+	}
+
+	var actual Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result: &actual,
+		DecodeHook: ComposeDecodeHookFunc(
+			StringToTimeDurationHookFunc(),
+			StringToTimeHookFunc(time.RFC3339),
+			StringToURLHookFunc(),
+			StringToIPHookFunc(),
+			StringToIPNetHookFunc(),
+			StringToSliceHookFunc(","),
+			TextUnmarshallerHookFunc(),
+			StringToBasicTypeHookFunc(),
+		),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(encoded); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, actual)
+	}
+}
+
+func TestDecoder_EncodeHookError(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name string `mapstructure:"name"`
+	}
+
+	var actual map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result: &actual,
+		EncodeHook: func(from reflect.Value) (interface{}, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(Config{Name: "x"})
+	if err == nil || !strings.Contains(err.Error(), "'.Name' boom") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEncode_elements(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Timeouts []time.Duration     `mapstructure:"timeouts"`
+		Hosts    map[string]net.IP   `mapstructure:"hosts"`
+		Windows  [2]time.Duration    `mapstructure:"windows"`
+		Nested   map[string][]net.IP `mapstructure:"nested"`
+		Names    []string            `mapstructure:"names"`
+	}
+
+	input := Config{
+		Timeouts: []time.Duration{time.Second, time.Minute},
+		Hosts:    map[string]net.IP{"primary": net.ParseIP("10.0.0.1")},
+		Windows:  [2]time.Duration{time.Hour, 2 * time.Hour},
+		Nested:   map[string][]net.IP{"dns": {net.ParseIP("1.1.1.1")}},
+		Names:    []string{"a", "b"},
+	}
+
+	encoded, err := Encode(input, &EncoderConfig{
+		EncodeHook: ComposeEncodeHookFunc(
+			TimeDurationToStringHookFunc(),
+			IPToStringHookFunc(),
+		),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedMap := map[string]interface{}{
+		"timeouts": []interface{}{"1s", "1m0s"},
+		"hosts":    map[string]interface{}{"primary": "10.0.0.1"},
+		"windows":  []interface{}{"1h0m0s", "2h0m0s"},
+		"nested":   map[string]interface{}{"dns": []interface{}{"1.1.1.1"}},
+		"names":    []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(encoded, expectedMap) {
+		t.Fatalf("Encode() expected: %#v\ngot: %#v", expectedMap, encoded)
+	}
+
+	var actual Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result: &actual,
+		DecodeHook: ComposeDecodeHookFunc(
+			StringToTimeDurationHookFunc(),
+			StringToIPHookFunc(),
+		),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(encoded); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(actual, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, actual)
+	}
+}
@@ -0,0 +1,276 @@
+package mapstructure
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/CoverWhale/mapstructure/v2/internal/errors"
+)
+
+// EnvConfig is the configuration used by DecodeEnv.
+type EnvConfig struct {
+	// Environ is the list of environment variables in the "KEY=value" form
+	// returned by os.Environ. If this is nil, os.Environ is used.
+	Environ []string
+
+	// Separator is placed between the prefix and the names of nested
+	// fields when building variable names. This defaults to "_".
+	Separator string
+
+	// SliceSeparator is used to split variables that are decoded into
+	// slices. This defaults to ",".
+	SliceSeparator string
+
+	// EnvTagName is the tag that overrides the name used for a field in
+	// its variable name. This defaults to "env".
+	EnvTagName string
+
+	// DecodeHook, if set, runs before the built-in hooks that convert
+	// strings to durations and slices. See DecoderConfig.
+	DecodeHook DecodeHookFunc
+
+	// If ErrorUnused is true, then it is an error for there to exist
+	// variables starting with the prefix that don't map onto any field.
+	ErrorUnused bool
+
+	// Metadata is the struct that will contain extra metadata about
+	// the decoding. If this is nil, then no metadata will be tracked.
+	Metadata *Metadata
+
+	// The tag name that mapstructure reads for field names. This
+	// defaults to "mapstructure".
+	TagName string
+
+	// Squash will squash embedded structs. See DecoderConfig.
+	Squash bool
+
+	// SquashTagOption is the tag option marking fields to squash. This
+	// defaults to "squash". See DecoderConfig.
+	SquashTagOption string
+}
+
+// DecodeEnv decodes environment variables into output, which must be a
+// pointer to a struct.
+//
+// The name of the variable for a field is built by joining the prefix and
+// the upper cased key of every field on the way to it with the separator.
+// For example, with the prefix "APP" the variable "APP_DB_HOST" is decoded
+// into the field DB.Host. The key of a field can be replaced using the
+// "env" tag, for example `env:"HOSTNAME"`. Map fields collect every
+// variable that starts with their name, using the lower cased remainder of
+// the variable name as the key.
+//
+// Decoding is always weakly typed, and strings are additionally converted
+// to time.Duration and, by splitting on the slice separator, to slices.
+//
+// If ErrorUnused is set and the prefix is not empty, variables starting
+// with the prefix that don't map onto any field are reported as unused keys
+// using their full variable name.
+func DecodeEnv(prefix string, output interface{}, config *EnvConfig) error {
+	if config == nil {
+		config = &EnvConfig{}
+	}
+
+	environ := config.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+
+	sep := config.Separator
+	if sep == "" {
+		sep = "_"
+	}
+
+	sliceSep := config.SliceSeparator
+	if sliceSep == "" {
+		sliceSep = ","
+	}
+
+	envTagName := config.EnvTagName
+	if envTagName == "" {
+		envTagName = "env"
+	}
+
+	tagName := config.TagName
+	if tagName == "" {
+		tagName = "mapstructure"
+	}
+
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[strings.ToUpper(k)] = v
+		}
+	}
+
+	typ := reflect.TypeOf(output)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return newDecodeError("", errors.New("result must be a pointer to a struct"))
+	}
+
+	hooks := []DecodeHookFunc{
+		StringToTimeDurationHookFunc(),
+		envStringToSliceHookFunc(sliceSep),
+	}
+	if config.DecodeHook != nil {
+		hooks = append([]DecodeHookFunc{config.DecodeHook}, hooks...)
+	}
+
+	decoder, err := NewDecoder(&DecoderConfig{
+		DecodeHook:       ComposeDecodeHookFunc(hooks...),
+		ErrorUnused:      config.ErrorUnused,
+		WeaklyTypedInput: true,
+		Squash:           config.Squash,
+		SquashTagOption:  config.SquashTagOption,
+		Metadata:         config.Metadata,
+		Result:           output,
+		TagName:          tagName,
+	})
+	if err != nil {
+		return err
+	}
+
+	b := &envBuilder{
+		env:        env,
+		used:       make(map[string]struct{}),
+		sep:        sep,
+		tagName:    tagName,
+		envTagName: envTagName,
+		squash:     config.Squash,
+		squashTag:  decoder.config.SquashTagOption,
+	}
+	input := b.build(typ, strings.ToUpper(prefix))
+
+	if prefix != "" {
+		prefix = strings.ToUpper(prefix) + sep
+		for k, v := range env {
+			if _, ok := b.used[k]; !ok && strings.HasPrefix(k, prefix) {
+				input[k] = v
+			}
+		}
+	}
+
+	return decoder.Decode(input)
+}
+
+// envBuilder builds the input map for DecodeEnv by walking the target type.
+type envBuilder struct {
+	env        map[string]string
+	used       map[string]struct{}
+	sep        string
+	tagName    string
+	envTagName string
+	squash     bool
+	squashTag  string
+}
+
+func (b *envBuilder) varName(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+
+	return prefix + b.sep + segment
+}
+
+func (b *envBuilder) build(typ reflect.Type, prefix string) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagParts := strings.Split(f.Tag.Get(b.tagName), ",")
+		if tagParts[0] == "-" {
+			continue
+		}
+
+		fieldType := f.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		squash := b.squash && f.Anonymous
+		remain := false
+		for _, tag := range tagParts[1:] {
+			switch tag {
+			case b.squashTag:
+				squash = true
+			case "remain":
+				remain = true
+			}
+		}
+		if remain {
+			continue
+		}
+
+		if squash && fieldType.Kind() == reflect.Struct {
+			for k, v := range b.build(fieldType, prefix) {
+				result[k] = v
+			}
+			continue
+		}
+
+		key := f.Name
+		if tagParts[0] != "" {
+			key = tagParts[0]
+		}
+
+		segment := strings.ToUpper(key)
+		if envTag := f.Tag.Get(b.envTagName); envTag != "" {
+			segment = strings.ToUpper(envTag)
+		}
+		name := b.varName(prefix, segment)
+
+		// A variable matching the field exactly always wins, which allows
+		// struct types such as time.Time to be set through decode hooks.
+		if v, ok := b.env[name]; ok {
+			b.used[name] = struct{}{}
+			result[key] = v
+			continue
+		}
+
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			if nested := b.build(fieldType, name); len(nested) > 0 {
+				result[key] = nested
+			}
+		case reflect.Map:
+			if fieldType.Key().Kind() != reflect.String {
+				continue
+			}
+
+			nested := make(map[string]interface{})
+			for k, v := range b.env {
+				if rest := strings.TrimPrefix(k, name+b.sep); rest != k && rest != "" {
+					b.used[k] = struct{}{}
+					nested[strings.ToLower(rest)] = v
+				}
+			}
+			if len(nested) > 0 {
+				result[key] = nested
+			}
+		}
+	}
+
+	return result
+}
+
+// envStringToSliceHookFunc applies StringToSliceHookFunc to any slice
+// target, leaving the conversion of the elements to weak typing.
+func envStringToSliceHookFunc(sep string) DecodeHookFuncType {
+	hook := StringToSliceHookFunc(sep).(func(reflect.Type, reflect.Type, interface{}) (interface{}, error))
+
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+
+		return hook(f, reflect.SliceOf(f), data)
+	}
+}
@@ -0,0 +1,151 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeEnv(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		Host string
+		Port int
+	}
+
+	type Common struct {
+		Debug bool
+	}
+
+	type Config struct {
+		Common  `mapstructure:",squash"`
+		DB      DB            `mapstructure:"db"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		Ports   []int         `mapstructure:"ports"`
+		Tags    []string      `mapstructure:"tags"`
+		Name    string        `mapstructure:"name" env:"SERVICE_NAME"`
+		Labels  map[string]string
+		Ignored string `mapstructure:"-"`
+	}
+
+	environ := []string{
+		"APP_DEBUG=true",
+		"APP_DB_HOST=localhost",
+		"APP_DB_PORT=5432",
+		"APP_TIMEOUT=5s",
+		"APP_PORTS=80,443",
+		"APP_TAGS=a,b",
+		"APP_SERVICE_NAME=api",
+		"APP_LABELS_TEAM=infra",
+		"APP_IGNORED=nope",
+		"OTHER_DB_HOST=remote",
+	}
+
+	var actual Config
+	if err := DecodeEnv("APP", &actual, &EnvConfig{Environ: environ}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Config{
+		Common:  Common{Debug: true},
+		DB:      DB{Host: "localhost", Port: 5432},
+		Timeout: 5 * time.Second,
+		Ports:   []int{80, 443},
+		Tags:    []string{"a", "b"},
+		Name:    "api",
+		Labels:  map[string]string{"team": "infra"},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeEnv() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestDecodeEnv_Separator(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		DB struct {
+			MaxConns int `mapstructure:"max_conns"`
+		} `mapstructure:"db"`
+	}
+
+	environ := []string{"APP__DB__MAX_CONNS=10"}
+
+	var actual Config
+	if err := DecodeEnv("APP", &actual, &EnvConfig{Environ: environ, Separator: "__"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual.DB.MaxConns != 10 {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestDecodeEnv_SquashTagOption(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		Host string `mapstructure:"host"`
+	}
+
+	type Config struct {
+		DB `mapstructure:",inline"`
+	}
+
+	environ := []string{"APP_HOST=localhost"}
+
+	var actual Config
+	if err := DecodeEnv("APP", &actual, &EnvConfig{Environ: environ, SquashTagOption: "inline"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual.Host != "localhost" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestDecodeEnv_ErrorUnused(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Host string
+	}
+
+	environ := []string{"APP_HOST=localhost", "APP_HOTS=typo", "HOME=/root"}
+
+	var actual Config
+	err := DecodeEnv("APP", &actual, &EnvConfig{Environ: environ, ErrorUnused: true})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if !strings.Contains(err.Error(), "APP_HOTS") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if strings.Contains(err.Error(), "HOME") {
+		t.Fatalf("variables without the prefix should be ignored: %s", err)
+	}
+}
+
+func TestDecodeEnv_InvalidValue(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Port int
+	}
+
+	var actual Config
+	err := DecodeEnv("APP", &actual, &EnvConfig{Environ: []string{"APP_PORT=http"}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var derr *DecodeError
+	if !errors.As(err, &derr) || derr.Name() != "Port" {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/CoverWhale/mapstructure/v2/internal/errors"
+)
+
+const (
+	// CollectAllErrors is the MaxErrors value that collects every error.
+	CollectAllErrors = 0
+
+	// FailFast is the MaxErrors value that stops at the first error.
+	FailFast = 1
+)
+
+// errorState tracks the errors collected during one call to Decode, to
+// enforce MaxErrors. Every call gets its own, so that a Decoder can be used
+// from several goroutines at once.
+type errorState struct {
+	count int
+
+	// joined holds the errors returned by joinErrors. Their errors were
+	// counted when they were added, so they aren't counted again when the
+	// joined error is added by the caller.
+	joined map[error]struct{}
+}
+
+func newErrorState() *errorState {
+	return &errorState{joined: make(map[error]struct{})}
+}
+
+// uncounted returns the number of errors in err that weren't counted yet.
+func (s *errorState) uncounted(err error) int {
+	if t := reflect.TypeOf(err); t != nil && t.Comparable() {
+		if _, ok := s.joined[err]; ok {
+			return 0
+		}
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return 1
+	}
+
+	n := 0
+	for _, err := range joined.Unwrap() {
+		n += s.uncounted(err)
+	}
+	return n
+}
+
+// addError appends err to errs and reports whether decoding must stop
+// because the limit set by MaxErrors was reached.
+func (d *Decoder) addError(errs []error, err error) ([]error, bool) {
+	errs = append(errs, err)
+	if d.errState == nil {
+		return errs, false
+	}
+
+	d.errState.count += d.errState.uncounted(err)
+	return errs, d.errState.count >= d.config.MaxErrors
+}
+
+// joinErrors joins the errors collected with addError so that they can be
+// returned. They were counted already, which the error state remembers for
+// when the caller adds the result to its own errors.
+func (d *Decoder) joinErrors(errs []error) error {
+	err := errors.Join(errs...)
+	if err != nil && d.errState != nil {
+		d.errState.joined[err] = struct{}{}
+	}
+
+	return err
+}
+
+// sortMapKeys sorts the keys of a map so that they are decoded, and their
+// errors reported, in a predictable order.
+func sortMapKeys(keys []reflect.Value) {
+	if len(keys) < 2 {
+		return
+	}
+
+	if keys[0].Kind() == reflect.String {
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+}
@@ -0,0 +1,205 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// errorNames returns the names of the DecodeErrors joined in err, in order.
+func errorNames(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var names []string
+		for _, err := range joined.Unwrap() {
+			names = append(names, errorNames(err)...)
+		}
+		return names
+	}
+
+	if e, ok := err.(*DecodeError); ok {
+		return []string{e.Name()}
+	}
+
+	if err := errors.Unwrap(err); err != nil {
+		return errorNames(err)
+	}
+
+	return nil
+}
+
+func TestDecoder_MaxErrors(t *testing.T) {
+	t.Parallel()
+
+	type Ints struct {
+		A int
+		B []int
+		C map[string]int
+		D int
+	}
+
+	type Strings struct {
+		A string
+		B string
+		C string
+	}
+
+	type Target struct {
+		A int
+		B int
+		C int
+	}
+
+	cases := []struct {
+		name     string
+		input    interface{}
+		weak     bool
+		result   func() interface{}
+		expected map[int][]string
+	}{
+		{
+			name: "struct from map",
+			input: map[string]interface{}{
+				"a": "x",
+				"b": []interface{}{1, "x", "y"},
+				"c": map[string]interface{}{"z": "x", "y": "x"},
+				"d": "x",
+			},
+			result: func() interface{} { return &Ints{} },
+			expected: map[int][]string{
+				CollectAllErrors: {"A", "B[1]", "B[2]", "C[y]", "C[z]", "D"},
+				FailFast:         {"A"},
+				3:                {"A", "B[1]", "B[2]"},
+				4:                {"A", "B[1]", "B[2]", "C[y]"},
+			},
+		},
+		{
+			name:   "map from map",
+			input:  map[string]interface{}{"c": "x", "a": "x", "b": "x"},
+			result: func() interface{} { return &map[string]int{} },
+			expected: map[int][]string{
+				CollectAllErrors: {"[a]", "[b]", "[c]"},
+				FailFast:         {"[a]"},
+				2:                {"[a]", "[b]"},
+			},
+		},
+		{
+			name: "map from slice",
+			input: []interface{}{
+				map[string]interface{}{"a": "x"},
+				map[string]interface{}{"b": "x"},
+			},
+			weak:   true,
+			result: func() interface{} { return &map[string]int{} },
+			expected: map[int][]string{
+				CollectAllErrors: {"[0][a]", "[1][b]"},
+				FailFast:         {"[0][a]"},
+			},
+		},
+		{
+			name:   "map from struct",
+			input:  Strings{A: "a", B: "b", C: "c"},
+			result: func() interface{} { return &map[string]int{} },
+			expected: map[int][]string{
+				CollectAllErrors: {".A", ".B", ".C"},
+				FailFast:         {".A"},
+				2:                {".A", ".B"},
+			},
+		},
+		{
+			name:   "struct from struct",
+			input:  Strings{A: "a", B: "b", C: "c"},
+			result: func() interface{} { return &Target{} },
+			expected: map[int][]string{
+				CollectAllErrors: {"A", "B", "C"},
+				FailFast:         {"A"},
+				2:                {"A", "B"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		for maxErrors, expected := range tc.expected {
+			decoder, err := NewDecoder(&DecoderConfig{
+				Result:           tc.result(),
+				WeaklyTypedInput: tc.weak,
+				MaxErrors:        maxErrors,
+			})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			// Decode twice to make sure nothing carries over.
+			for i := 0; i < 2; i++ {
+				err = decoder.Decode(tc.input)
+				if err == nil {
+					t.Fatalf("%s: expected error", tc.name)
+				}
+
+				actual := errorNames(err)
+				if !reflect.DeepEqual(actual, expected) {
+					t.Fatalf("%s: MaxErrors=%d: expected errors for %v, got %v (%s)", tc.name, maxErrors, expected, actual, err)
+				}
+			}
+		}
+	}
+}
+
+func TestDecoder_MaxErrorsValidate(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name  string `validate:"nonempty,min=3"`
+		Ports []int  `validate:"len=2"`
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result:    &result,
+		Validate:  true,
+		MaxErrors: FailFast,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(map[string]interface{}{"ports": []int{1}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if actual := errorNames(err); !reflect.DeepEqual(actual, []string{"Name"}) {
+		t.Fatalf("expected a single error for Name, got %v (%s)", actual, err)
+	}
+}
+
+func TestDecoder_MaxErrorsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		A int
+		B int
+		C int
+	}
+
+	var result Target
+	decoder, err := NewDecoder(&DecoderConfig{MaxErrors: 2, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	input := map[string]interface{}{"a": "x", "b": "x", "c": "x"}
+	expected := []string{"A", "B"}
+
+	// Calls on the same Decoder count their errors separately.
+	done := make(chan []string)
+	for i := 0; i < 8; i++ {
+		go func() {
+			done <- errorNames(decoder.Decode(input))
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if names := <-done; !reflect.DeepEqual(names, expected) {
+			t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, names)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package mapstructure
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // Error interface is implemented by all errors emitted by mapstructure.
@@ -72,3 +73,52 @@ func (e *UnconvertibleTypeError) Error() string {
 }
 
 func (*UnconvertibleTypeError) mapstructure() {}
+
+// ValidationError is an error type that indicates a decoded value failed
+// one of the rules in its validate tag.
+type ValidationError struct {
+	Rule  string
+	Param string
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	rule := e.Rule
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+
+	return fmt.Sprintf("failed validation '%s': %s", rule, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (*ValidationError) mapstructure() {}
+
+// UnusedKeysError is an error type that indicates the input had keys that
+// don't match any field of the struct it was decoded into, reported when
+// ErrorUnused is set.
+type UnusedKeysError struct {
+	// Keys are the unused keys, sorted.
+	Keys []string
+
+	// Suggestions maps unused keys to the closest matching field names of
+	// the struct, if there are any close enough to be likely typos.
+	Suggestions map[string][]string
+}
+
+func (e *UnusedKeysError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		keys[i] = key
+		if suggestions := e.Suggestions[key]; len(suggestions) > 0 {
+			keys[i] += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, " or "))
+		}
+	}
+
+	return fmt.Sprintf("has invalid keys: %s", strings.Join(keys, ", "))
+}
+
+func (*UnusedKeysError) mapstructure() {}
@@ -0,0 +1,373 @@
+package mapstructure
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/CoverWhale/mapstructure/v2/internal/errors"
+)
+
+// FlagConfig is the configuration used to convert between flag sets and
+// structs.
+type FlagConfig struct {
+	// KeyPath maps the name of a flag onto the path of keys it is decoded
+	// into. This defaults to splitting the name on "-", so that the flag
+	// "db-host" is decoded into {"db": {"host": ...}}. Flags defined by
+	// DefineFlags don't need it since they remember the path of their
+	// field.
+	KeyPath func(name string) []string
+
+	// FlagName builds the name of a flag from the path of keys of a field.
+	// This defaults to joining the lower cased keys with "-".
+	FlagName func(path []string) string
+
+	// UsageTagName is the tag holding the usage string of a flag. This
+	// defaults to "usage".
+	UsageTagName string
+
+	// DecodeHook, if set, runs before the built-in hooks. See DecoderConfig.
+	DecodeHook DecodeHookFunc
+
+	// If ErrorUnused is true, then it is an error for a set flag to not map
+	// onto any field.
+	ErrorUnused bool
+
+	// Metadata is the struct that will contain extra metadata about
+	// the decoding. If this is nil, then no metadata will be tracked.
+	Metadata *Metadata
+
+	// The tag name that mapstructure reads for field names. This
+	// defaults to "mapstructure".
+	TagName string
+
+	// Squash will squash embedded structs. See DecoderConfig.
+	Squash bool
+
+	// SquashTagOption is the tag option marking fields to squash. This
+	// defaults to "squash". See DecoderConfig.
+	SquashTagOption string
+}
+
+func (c *FlagConfig) keyPath(name string) []string {
+	if c.KeyPath != nil {
+		return c.KeyPath(name)
+	}
+
+	return strings.Split(name, "-")
+}
+
+func (c *FlagConfig) flagName(path []string) string {
+	if c.FlagName != nil {
+		return c.FlagName(path)
+	}
+
+	return strings.ToLower(strings.Join(path, "-"))
+}
+
+func (c *FlagConfig) tagName() string {
+	if c.TagName != "" {
+		return c.TagName
+	}
+
+	return "mapstructure"
+}
+
+func (c *FlagConfig) squashTagOption() string {
+	if c.SquashTagOption != "" {
+		return c.SquashTagOption
+	}
+
+	return "squash"
+}
+
+// fieldFlag is the value of a flag defined by DefineFlags. It remembers
+// the path of keys of its field, which names built by FlagName can't
+// always be split back into.
+type fieldFlag struct {
+	flag.Value
+	path []string
+}
+
+func (f *fieldFlag) String() string {
+	// flag.FlagSet calls String on a zero value to find out whether the
+	// default is zero.
+	if f.Value == nil {
+		return ""
+	}
+
+	return f.Value.String()
+}
+
+func (f *fieldFlag) Get() interface{} {
+	if getter, ok := f.Value.(flag.Getter); ok {
+		return getter.Get()
+	}
+
+	return f.Value.String()
+}
+
+func (f *fieldFlag) IsBoolFlag() bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// FlagSetInput converts the flags of fs that were explicitly set on the
+// command line into a map that can be used as input for decoding. Flags
+// that were not set are left out so that they don't override values that
+// are already present in the result. Flags defined by DefineFlags are
+// decoded into the field they were defined for; the key paths of other
+// flags are built using the KeyPath function of the configuration.
+//
+// Values are taken from flag.Getter if the flag implements it, which all
+// of the flag types of the standard library do, and from the string
+// representation of the flag otherwise.
+//
+// An error is returned if the key path of a flag runs through the value of
+// another, as "db-host" does through "db".
+func FlagSetInput(fs *flag.FlagSet, config *FlagConfig) (map[string]interface{}, error) {
+	if config == nil {
+		config = &FlagConfig{}
+	}
+
+	result := make(map[string]interface{})
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+
+		var value interface{} = f.Value.String()
+		if getter, ok := f.Value.(flag.Getter); ok {
+			value = getter.Get()
+		}
+
+		path := config.keyPath(f.Name)
+		if ff, ok := f.Value.(*fieldFlag); ok {
+			path = ff.path
+		}
+		m := result
+		for _, key := range path[:len(path)-1] {
+			existing, ok := m[key]
+			if !ok {
+				nested := make(map[string]interface{})
+				m[key] = nested
+				m = nested
+				continue
+			}
+
+			nested, ok := existing.(map[string]interface{})
+			if !ok {
+				err = newDecodeError(f.Name, fmt.Errorf("conflicts with value of %q", key))
+				return
+			}
+			m = nested
+		}
+
+		last := path[len(path)-1]
+		if _, ok := m[last].(map[string]interface{}); ok {
+			err = newDecodeError(f.Name, fmt.Errorf("conflicts with nested keys of %q", last))
+			return
+		}
+		m[last] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DecodeFlags decodes the flags of fs that were explicitly set onto output.
+// See FlagSetInput for how flags are mapped onto keys.
+//
+// Decoding is always weakly typed, and strings are additionally converted
+// to time.Duration, to types implementing encoding.TextUnmarshaler and, by
+// splitting on ",", to slices.
+func DecodeFlags(fs *flag.FlagSet, output interface{}, config *FlagConfig) error {
+	if config == nil {
+		config = &FlagConfig{}
+	}
+
+	hooks := []DecodeHookFunc{
+		StringToTimeDurationHookFunc(),
+		envStringToSliceHookFunc(","),
+		TextUnmarshallerHookFunc(),
+	}
+	if config.DecodeHook != nil {
+		hooks = append([]DecodeHookFunc{config.DecodeHook}, hooks...)
+	}
+
+	decoder, err := NewDecoder(&DecoderConfig{
+		DecodeHook:       ComposeDecodeHookFunc(hooks...),
+		ErrorUnused:      config.ErrorUnused,
+		WeaklyTypedInput: true,
+		Squash:           config.Squash,
+		SquashTagOption:  config.squashTagOption(),
+		Metadata:         config.Metadata,
+		Result:           output,
+		TagName:          config.tagName(),
+	})
+	if err != nil {
+		return err
+	}
+
+	input, err := FlagSetInput(fs, config)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// DefineFlags defines a flag on fs for every field of input, which must be
+// a struct or a pointer to a struct. The current values of the fields are
+// used as the defaults of the flags and the usage strings are read from
+// the "usage" tag.
+//
+// The name of a flag is built from the path of keys of its field using the
+// FlagName function of the configuration, so that "db-host" is defined for
+// the field DB.Host by default. Strings, bools, numbers and durations get
+// flags of the matching type. Slices and types implementing
+// encoding.TextMarshaler are defined as string flags, which DecodeFlags
+// converts back. Fields of other types, such as maps, are skipped. An error
+// is returned if two fields, for example of squashed structs, would define
+// a flag of the same name, or if a flag of that name is already defined.
+func DefineFlags(fs *flag.FlagSet, input interface{}, config *FlagConfig) error {
+	if config == nil {
+		config = &FlagConfig{}
+	}
+
+	val := reflect.Indirect(reflect.ValueOf(input))
+	if val.Kind() != reflect.Struct {
+		return errors.New("input must be a struct or a pointer to a struct")
+	}
+
+	return defineFlags(fs, val, nil, config)
+}
+
+func defineFlags(fs *flag.FlagSet, val reflect.Value, path []string, config *FlagConfig) error {
+	usageTagName := config.UsageTagName
+	if usageTagName == "" {
+		usageTagName = "usage"
+	}
+
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagParts := strings.Split(f.Tag.Get(config.tagName()), ",")
+		if tagParts[0] == "-" {
+			continue
+		}
+
+		v := val.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v = reflect.Zero(v.Type().Elem())
+			} else {
+				v = v.Elem()
+			}
+		}
+
+		squash := config.Squash && f.Anonymous
+		remain := false
+		for _, tag := range tagParts[1:] {
+			switch tag {
+			case config.squashTagOption():
+				squash = true
+			case "remain":
+				remain = true
+			}
+		}
+		if remain {
+			continue
+		}
+
+		if squash && v.Kind() == reflect.Struct {
+			if err := defineFlags(fs, v, path, config); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := f.Name
+		if tagParts[0] != "" {
+			key = tagParts[0]
+		}
+
+		fieldPath := append(append([]string(nil), path...), key)
+		name := config.flagName(fieldPath)
+		usage := f.Tag.Get(usageTagName)
+
+		// FlagSet panics when a flag is defined twice.
+		if definesFlag(v) && fs.Lookup(name) != nil {
+			return newDecodeError(strings.Join(fieldPath, "."), fmt.Errorf("flag %q is already defined", name))
+		}
+
+		if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return newDecodeError(strings.Join(fieldPath, "."), err)
+			}
+			fs.String(name, string(text), usage)
+		} else if v.Type() == reflect.TypeOf(time.Duration(0)) {
+			fs.Duration(name, time.Duration(v.Int()), usage)
+		} else {
+			switch getKind(v) {
+			case reflect.String:
+				fs.String(name, v.String(), usage)
+			case reflect.Bool:
+				fs.Bool(name, v.Bool(), usage)
+			case reflect.Int:
+				fs.Int64(name, v.Int(), usage)
+			case reflect.Uint:
+				fs.Uint64(name, v.Uint(), usage)
+			case reflect.Float32:
+				fs.Float64(name, v.Float(), usage)
+			case reflect.Slice, reflect.Array:
+				elems := make([]string, v.Len())
+				for i := range elems {
+					elems[i] = fmt.Sprint(v.Index(i).Interface())
+				}
+				fs.String(name, strings.Join(elems, ","), usage)
+			case reflect.Struct:
+				if err := defineFlags(fs, v, fieldPath, config); err != nil {
+					return err
+				}
+				continue
+			default:
+				// Maps, interfaces and the like have no sensible flag
+				// representation, so they are left out.
+				continue
+			}
+		}
+
+		// Remember the field of the flag for FlagSetInput.
+		defined := fs.Lookup(name)
+		defined.Value = &fieldFlag{Value: defined.Value, path: fieldPath}
+	}
+
+	return nil
+}
+
+// definesFlag reports whether defineFlags defines a flag for v itself, as
+// opposed to flags for its fields or none at all.
+func definesFlag(v reflect.Value) bool {
+	if _, ok := v.Interface().(encoding.TextMarshaler); ok {
+		return true
+	}
+
+	switch getKind(v) {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Uint, reflect.Float32, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,217 @@
+package mapstructure
+
+import (
+	"flag"
+	"io"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlagSetInput(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db-host", "localhost", "")
+	fs.Int("db-port", 5432, "")
+	fs.Bool("verbose", false, "")
+
+	if err := fs.Parse([]string{"--db-host", "remote", "--verbose"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"db":      map[string]interface{}{"host": "remote"},
+		"verbose": true,
+	}
+	actual, err := FlagSetInput(fs, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("FlagSetInput() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestFlagSetInput_conflict(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db", "", "")
+	fs.String("db-host", "", "")
+
+	if err := fs.Parse([]string{"--db", "x", "--db-host", "remote"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, err := FlagSetInput(fs, nil)
+	if err == nil || err.Error() != `'db-host' conflicts with value of "db"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeFlags(t *testing.T) {
+	t.Parallel()
+
+	type DB struct {
+		Host     string `mapstructure:"host" usage:"database host"`
+		Port     int    `mapstructure:"port"`
+		MaxConns uint   `mapstructure:"max_conns"`
+	}
+
+	type Config struct {
+		DB      DB            `mapstructure:"db"`
+		Timeout time.Duration `mapstructure:"timeout"`
+		Tags    []string      `mapstructure:"tags"`
+		Addr    netip.Addr    `mapstructure:"addr"`
+		Ratio   float32       `mapstructure:"ratio"`
+		Verbose bool          `mapstructure:"verbose"`
+		Labels  map[string]string
+	}
+
+	defaults := Config{
+		DB:      DB{Host: "localhost", Port: 5432, MaxConns: 10},
+		Timeout: time.Second,
+		Tags:    []string{"a"},
+		Addr:    netip.MustParseAddr("127.0.0.1"),
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := DefineFlags(fs, &defaults, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if f := fs.Lookup("db-host"); f == nil || f.DefValue != "localhost" || f.Usage != "database host" {
+		t.Fatalf("bad flag: %#v", f)
+	}
+	if f := fs.Lookup("tags"); f == nil || f.DefValue != "a" {
+		t.Fatalf("bad flag: %#v", f)
+	}
+
+	args := []string{
+		"--db-port=6543",
+		"--db-max_conns=20",
+		"--timeout=5s",
+		"--tags=b,c",
+		"--addr=10.0.0.1",
+		"--ratio=0.5",
+		"--verbose",
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := defaults
+	if err := DecodeFlags(fs, &actual, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Config{
+		DB:      DB{Host: "localhost", Port: 6543, MaxConns: 20},
+		Timeout: 5 * time.Second,
+		Tags:    []string{"b", "c"},
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Ratio:   0.5,
+		Verbose: true,
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeFlags() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestDecodeFlags_KeyPath(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		DB struct {
+			Host string `mapstructure:"host"`
+		} `mapstructure:"db"`
+	}
+
+	config := &FlagConfig{
+		KeyPath:  func(name string) []string { return strings.Split(name, ".") },
+		FlagName: func(path []string) string { return strings.Join(path, ".") },
+	}
+
+	var actual Config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := DefineFlags(fs, &actual, config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := fs.Parse([]string{"--db.host=remote"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := DecodeFlags(fs, &actual, config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual.DB.Host != "remote" {
+		t.Fatalf("bad: %#v", actual)
+	}
+}
+
+func TestDefineFlags_duplicate(t *testing.T) {
+	t.Parallel()
+
+	type Common struct {
+		Name string `mapstructure:"name"`
+	}
+
+	type Config struct {
+		Common `mapstructure:",squash"`
+		Name   string `mapstructure:"name"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	err := DefineFlags(fs, &Config{}, nil)
+	if err == nil || err.Error() != `'name' flag "name" is already defined` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDefineFlags_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	type Common struct {
+		Debug bool `mapstructure:"debug"`
+	}
+
+	type Config struct {
+		Common   `mapstructure:",inline"`
+		LogLevel string `mapstructure:"log-level"`
+		DB       struct {
+			Host string
+		} `mapstructure:"db"`
+	}
+
+	config := &FlagConfig{SquashTagOption: "inline"}
+
+	var actual Config
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := DefineFlags(fs, &actual, config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	fs.PrintDefaults()
+
+	if err := fs.Parse([]string{"--log-level=debug", "--db-host", "remote", "--debug"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := DecodeFlags(fs, &actual, config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected Config
+	expected.Debug = true
+	expected.LogLevel = "debug"
+	expected.DB.Host = "remote"
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeFlags() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
@@ -0,0 +1,309 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CoverWhale/mapstructure/v2/internal/errors"
+)
+
+// GeneratedLayout describes the tag configuration a type's generated
+// methods were written for. The Decoder only uses the generated methods
+// when its own configuration matches the layout.
+type GeneratedLayout struct {
+	TagName              string
+	Squash               bool
+	IgnoreUntaggedFields bool
+}
+
+// GeneratedDecoder is implemented by pointers to types with methods
+// generated by mapstructure-gen. The Decoder calls DecodeMapstructureWith
+// instead of walking the fields of the struct through reflection when
+// decoding a map[string]interface{} into it, unless its configuration needs
+// features the generated code doesn't support, such as decode hooks,
+// Metadata or a custom MatchName. Fields of basic types are assigned
+// directly, while other fields are still decoded by the Decoder.
+type GeneratedDecoder interface {
+	MapstructureLayout() GeneratedLayout
+	DecodeMapstructureWith(g GenSupport, name string, input map[string]interface{}) error
+}
+
+// GeneratedEncoder is implemented by types with methods generated by
+// mapstructure-gen. The Decoder calls EncodeMapstructureWith instead of
+// walking the fields of the struct through reflection when decoding it into
+// a map[string]interface{}, under the same conditions as for
+// GeneratedDecoder.
+type GeneratedEncoder interface {
+	MapstructureLayout() GeneratedLayout
+	EncodeMapstructureWith(g GenSupport, name string) (map[string]interface{}, error)
+}
+
+// GenSupport gives the code generated by mapstructure-gen access to the
+// Decoder that calls it. It is internal to the generated code: its methods
+// may change along with mapstructure-gen and aren't meant to be called
+// otherwise.
+type GenSupport struct {
+	d *Decoder
+}
+
+var (
+	generatedDecoderType   = reflect.TypeOf((*GeneratedDecoder)(nil)).Elem()
+	generatedEncoderType   = reflect.TypeOf((*GeneratedEncoder)(nil)).Elem()
+	mapStringInterfaceType = reflect.TypeOf((map[string]interface{})(nil))
+)
+
+// DecodeGenerated decodes input into target like Decode with config would.
+// It is used by the DecodeMapstructure methods written by mapstructure-gen.
+func DecodeGenerated(target GeneratedDecoder, input map[string]interface{}, config *DecoderConfig) error {
+	c := *config
+	c.Result = target
+
+	decoder, err := NewDecoder(&c)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// EncodeGenerated decodes value into a map like Decode with config would.
+// It is used by the EncodeMapstructure methods written by mapstructure-gen.
+func EncodeGenerated(value GeneratedEncoder, config *DecoderConfig) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	c := *config
+	c.Result = &result
+
+	decoder, err := NewDecoder(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(value); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// canUseGenerated reports whether config only uses features that the
+// methods generated by mapstructure-gen implement. It must be called before
+// NewDecoder fills in the defaults.
+func canUseGenerated(config *DecoderConfig) bool {
+	return !config.IgnoreGenerated &&
+		config.DecodeHook == nil &&
+		len(config.PathHooks) == 0 &&
+		config.HookRegistry == nil &&
+		config.EncodeHook == nil &&
+		config.Redaction == nil &&
+		config.Metadata == nil &&
+		config.MatchName == nil &&
+		config.DeprecationHandler == nil &&
+		(config.SquashTagOption == "" || config.SquashTagOption == "squash") &&
+		config.KeyCollisions == KeyCollisionIgnore &&
+		config.KeyDelimiter == "" &&
+		!config.CallLifecycleMethods &&
+		config.MaxErrors == CollectAllErrors
+}
+
+func (d *Decoder) generatedLayout() GeneratedLayout {
+	return GeneratedLayout{
+		TagName:              d.config.TagName,
+		Squash:               d.config.Squash,
+		IgnoreUntaggedFields: d.config.IgnoreUntaggedFields,
+	}
+}
+
+// generatedDecoder returns the generated methods to decode dataVal into
+// val, if there are any that can be used.
+func (d *Decoder) generatedDecoder(dataVal, val reflect.Value) GeneratedDecoder {
+	if !d.useGenerated || dataVal.Type() != mapStringInterfaceType || !val.CanAddr() {
+		return nil
+	}
+
+	ptr := val.Addr()
+	if !ptr.Type().Implements(generatedDecoderType) {
+		return nil
+	}
+
+	gen := ptr.Interface().(GeneratedDecoder)
+	if gen.MapstructureLayout() != d.generatedLayout() {
+		return nil
+	}
+
+	return gen
+}
+
+// generatedEncoder returns the generated methods to decode dataVal into
+// valMap, if there are any that can be used.
+func (d *Decoder) generatedEncoder(dataVal, valMap reflect.Value) GeneratedEncoder {
+	if !d.useGenerated || valMap.Type() != mapStringInterfaceType || !dataVal.Type().Implements(generatedEncoderType) {
+		return nil
+	}
+
+	gen := dataVal.Interface().(GeneratedEncoder)
+	if gen.MapstructureLayout() != d.generatedLayout() {
+		return nil
+	}
+
+	return gen
+}
+
+// Lookup returns the key of input that a field named name is decoded
+// from, together with its value.
+func (g GenSupport) Lookup(input map[string]interface{}, name string) (string, interface{}, bool) {
+	if v, ok := input[name]; ok {
+		return name, v, true
+	}
+
+	for k, v := range input {
+		if g.d.config.MatchName(k, name) {
+			return k, v, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// DecodeField decodes input into the field that ptr points to.
+func (g GenSupport) DecodeField(name string, input interface{}, ptr interface{}) error {
+	return g.d.decode(name, input, generatedField(ptr))
+}
+
+// Unset adds the field that ptr points to to the unset fields, unless
+// AllowUnsetPointer exempts it.
+func (g GenSupport) Unset(unset []string, name string, ptr interface{}) []string {
+	if g.d.config.AllowUnsetPointer && generatedField(ptr).Kind() == reflect.Ptr {
+		return unset
+	}
+
+	return append(unset, name)
+}
+
+// generatedField returns the value of the field that ptr points to the way
+// decodeStructFromMap sees it: pointers to structs stand for the struct.
+func generatedField(ptr interface{}) reflect.Value {
+	v := reflect.ValueOf(ptr).Elem()
+	if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// Finish completes decoding a map into a struct once its fields were
+// decoded: the keys that weren't used are put into the "remain" field that
+// remain points to, if there is one, and ErrorUnused and ErrorUnset are
+// enforced. names are the keys of the exported fields of the struct, to
+// suggest in place of unused keys.
+func (g GenSupport) Finish(name string, input map[string]interface{}, names, used, unset []string, remain interface{}, errs []error) error {
+	var unused []string
+	if len(used) < len(input) {
+		usedKeys := make(map[string]struct{}, len(used))
+		for _, k := range used {
+			usedKeys[k] = struct{}{}
+		}
+		for k := range input {
+			if _, ok := usedKeys[k]; !ok {
+				unused = append(unused, k)
+			}
+		}
+	}
+
+	if remain != nil && len(unused) > 0 {
+		remainMap := make(map[interface{}]interface{}, len(unused))
+		for _, k := range unused {
+			remainMap[k] = input[k]
+		}
+
+		if err := g.d.decodeMap(name, remainMap, reflect.ValueOf(remain).Elem()); err != nil {
+			errs = append(errs, err)
+		}
+		unused = nil
+	}
+
+	sort.Strings(unused)
+	unused, _ = g.d.ignoreUnusedKeys(name, unused)
+
+	if g.d.config.ErrorUnused && len(unused) > 0 {
+		errs = append(errs, unusedKeysError(name, unused, names))
+	}
+
+	if g.d.config.ErrorUnset && len(unset) > 0 {
+		sort.Strings(unset)
+		unset = dedupeSorted(unset)
+		errs = append(errs, newDecodeError(
+			name,
+			fmt.Errorf("has unset fields: %s", strings.Join(unset, ", ")),
+		))
+	}
+
+	return errors.Join(errs...)
+}
+
+// EncodeField converts the field that ptr points to into a value of a
+// map, like decoding its struct into a map does. It returns false if the
+// field is omitted.
+func (g GenSupport) EncodeField(name string, ptr interface{}, omitEmpty, omitZero bool) (interface{}, bool, error) {
+	v := dereferencePtrToStructIfNeeded(reflect.ValueOf(ptr).Elem(), g.d.config.TagName)
+	if omitEmpty && isEmptyValue(v) || omitZero && v.IsZero() {
+		return nil, false, nil
+	}
+
+	// Tuple structs are encoded as slices, as decodeMapFromStructField
+	// does.
+	v, err := g.d.tupleFieldOutput(name, false, v)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if v.Kind() != reflect.Struct {
+		return v.Interface(), true, nil
+	}
+
+	x := reflect.New(v.Type())
+	x.Elem().Set(v)
+
+	m := make(map[string]interface{})
+	if err := g.d.decode(name, x.Interface(), reflect.ValueOf(&m).Elem()); err != nil {
+		return nil, false, err
+	}
+
+	return m, true, nil
+}
+
+// EncodeSquash converts the squashed struct field that ptr points to
+// into a map and copies its keys into m. The field is named field in Go and
+// keyName in the map.
+func (g GenSupport) EncodeSquash(name, field, keyName string, ptr interface{}, m map[string]interface{}) error {
+	v := dereferencePtrToStructIfNeeded(reflect.ValueOf(ptr).Elem(), g.d.config.TagName)
+	if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return newDecodeError(
+			name+"."+field,
+			fmt.Errorf("cannot squash non-struct type %q", v.Type()),
+		)
+	}
+
+	x := reflect.New(v.Type())
+	x.Elem().Set(v)
+
+	squashed := make(map[string]interface{})
+	if err := g.d.decode(keyName, x.Interface(), reflect.ValueOf(&squashed).Elem()); err != nil {
+		return err
+	}
+
+	for k, elem := range squashed {
+		m[k] = elem
+	}
+
+	return nil
+}
+
+// Join joins the errors of decoding a struct into a map.
+func (g GenSupport) Join(errs []error) error {
+	return errors.Join(errs...)
+}
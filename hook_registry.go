@@ -0,0 +1,144 @@
+package mapstructure
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// hookKey identifies the hooks registered for a pair of source and target
+// types. A nil type matches any type.
+type hookKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+// HookRegistry holds decode hooks registered for specific source and target
+// types. Unlike a chain built with ComposeDecodeHookFunc, where every hook
+// runs for every value, the registry only runs the hooks registered for the
+// types at hand and finds them with a single lookup per type pair.
+//
+// Hooks must be registered before the registry is used by a Decoder.
+type HookRegistry struct {
+	hooks    map[hookKey][]func(from reflect.Value, to reflect.Value) (interface{}, error)
+	resolved sync.Map
+}
+
+// NewHookRegistry returns an empty HookRegistry.
+func NewHookRegistry() *HookRegistry {
+	return &HookRegistry{
+		hooks: make(map[hookKey][]func(from reflect.Value, to reflect.Value) (interface{}, error)),
+	}
+}
+
+// Register adds hook for values of type from that are decoded into type to.
+// Either type may be nil to match any type. Any DecodeHookFunc can be
+// registered, including the existing StringTo*HookFunc family.
+func (r *HookRegistry) Register(from, to reflect.Type, hook DecodeHookFunc) {
+	key := hookKey{from: from, to: to}
+	r.hooks[key] = append(r.hooks[key], cachedDecodeHook(hook))
+
+	r.resolved.Range(func(k, _ interface{}) bool {
+		r.resolved.Delete(k)
+		return true
+	})
+}
+
+// RegisterHook adds a typed hook converting values of type From into type
+// To to the registry.
+func RegisterHook[From, To any](r *HookRegistry, fn func(From) (To, error)) {
+	from := reflect.TypeOf((*From)(nil)).Elem()
+	to := reflect.TypeOf((*To)(nil)).Elem()
+
+	r.Register(from, to, DecodeHookFuncValue(func(f reflect.Value, _ reflect.Value) (interface{}, error) {
+		return fn(f.Interface().(From))
+	}))
+}
+
+// RegisterStringHooks registers the hooks of the StringTo*HookFunc family
+// that don't take any parameters for their exact target types.
+func (r *HookRegistry) RegisterStringHooks() {
+	str := reflect.TypeOf("")
+
+	hooks := map[reflect.Type]DecodeHookFunc{
+		reflect.TypeOf(time.Duration(0)): StringToTimeDurationHookFunc(),
+		reflect.TypeOf(&url.URL{}):       StringToURLHookFunc(),
+		reflect.TypeOf(net.IP{}):         StringToIPHookFunc(),
+		reflect.TypeOf(net.IPNet{}):      StringToIPNetHookFunc(),
+		reflect.TypeOf(netip.Addr{}):     StringToNetIPAddrHookFunc(),
+		reflect.TypeOf(netip.AddrPort{}): StringToNetIPAddrPortHookFunc(),
+		reflect.TypeOf(netip.Prefix{}):   StringToNetIPPrefixHookFunc(),
+		reflect.TypeOf(int8(0)):          StringToInt8HookFunc(),
+		reflect.TypeOf(uint8(0)):         StringToUint8HookFunc(),
+		reflect.TypeOf(int16(0)):         StringToInt16HookFunc(),
+		reflect.TypeOf(uint16(0)):        StringToUint16HookFunc(),
+		reflect.TypeOf(int32(0)):         StringToInt32HookFunc(),
+		reflect.TypeOf(uint32(0)):        StringToUint32HookFunc(),
+		reflect.TypeOf(int64(0)):         StringToInt64HookFunc(),
+		reflect.TypeOf(uint64(0)):        StringToUint64HookFunc(),
+		reflect.TypeOf(int(0)):           StringToIntHookFunc(),
+		reflect.TypeOf(uint(0)):          StringToUintHookFunc(),
+		reflect.TypeOf(float32(0)):       StringToFloat32HookFunc(),
+		reflect.TypeOf(float64(0)):       StringToFloat64HookFunc(),
+		reflect.TypeOf(false):            StringToBoolHookFunc(),
+		reflect.TypeOf(complex64(0)):     StringToComplex64HookFunc(),
+		reflect.TypeOf(complex128(0)):    StringToComplex128HookFunc(),
+	}
+
+	for to, hook := range hooks {
+		r.Register(str, to, hook)
+	}
+}
+
+// lookup returns the hooks for a pair of types: first the ones registered
+// for the exact pair, then the ones for the target type and finally the
+// ones for the source type.
+func (r *HookRegistry) lookup(from, to reflect.Type) []func(from reflect.Value, to reflect.Value) (interface{}, error) {
+	key := hookKey{from: from, to: to}
+	if hooks, ok := r.resolved.Load(key); ok {
+		return hooks.([]func(from reflect.Value, to reflect.Value) (interface{}, error))
+	}
+
+	var hooks []func(from reflect.Value, to reflect.Value) (interface{}, error)
+	hooks = append(hooks, r.hooks[key]...)
+	hooks = append(hooks, r.hooks[hookKey{to: to}]...)
+	hooks = append(hooks, r.hooks[hookKey{from: from}]...)
+
+	r.resolved.Store(key, hooks)
+	return hooks
+}
+
+// DecodeHook returns a DecodeHookFunc that runs the hooks registered for
+// the types of each value. The hooks for a pair are chained like
+// ComposeDecodeHookFunc does.
+func (r *HookRegistry) DecodeHook() DecodeHookFuncValue {
+	return func(from reflect.Value, to reflect.Value) (interface{}, error) {
+		hooks := r.lookup(from.Type(), to.Type())
+		data := from.Interface()
+		if len(hooks) == 0 {
+			return data, nil
+		}
+
+		var err error
+		newFrom := from
+		for _, h := range hooks {
+			data, err = h(newFrom, to)
+			if err != nil {
+				return nil, err
+			}
+			if v, ok := data.(reflect.Value); ok {
+				newFrom = v
+			} else {
+				newFrom = reflect.ValueOf(data)
+			}
+			if !newFrom.IsValid() {
+				break
+			}
+		}
+
+		return data, nil
+	}
+}
@@ -0,0 +1,123 @@
+package mapstructure
+
+import (
+	"errors"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookRegistry(t *testing.T) {
+	t.Parallel()
+
+	type Level int
+
+	type Config struct {
+		Timeout time.Duration
+		Addr    netip.Addr
+		Link    *url.URL
+		Level   Level
+		Name    string
+	}
+
+	registry := NewHookRegistry()
+	registry.RegisterStringHooks()
+	RegisterHook(registry, func(s string) (Level, error) {
+		switch s {
+		case "debug":
+			return 0, nil
+		case "info":
+			return 1, nil
+		}
+		return 0, errors.New("unknown level")
+	})
+
+	input := map[string]interface{}{
+		"timeout": "5s",
+		"addr":    "10.0.0.1",
+		"link":    "https://example.com",
+		"level":   "info",
+		"name":    "plain",
+	}
+
+	var actual Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &actual, HookRegistry: registry})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	link, _ := url.Parse("https://example.com")
+	expected := Config{
+		Timeout: 5 * time.Second,
+		Addr:    netip.MustParseAddr("10.0.0.1"),
+		Link:    link,
+		Level:   1,
+		Name:    "plain",
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	err = decoder.Decode(map[string]interface{}{"level": "trace"})
+	if err == nil || !strings.Contains(err.Error(), "'Level' unknown level") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHookRegistry_WildcardsAndDecodeHook(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name  string
+		Count int
+	}
+
+	var calls []string
+	registry := NewHookRegistry()
+	registry.Register(nil, reflect.TypeOf(""), func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		calls = append(calls, "to string")
+		if s, ok := data.(string); ok {
+			return strings.TrimSpace(s), nil
+		}
+		return data, nil
+	})
+	registry.Register(reflect.TypeOf(""), nil, func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		calls = append(calls, "from string "+t.String())
+		return data, nil
+	})
+
+	var actual Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result:       &actual,
+		HookRegistry: registry,
+		DecodeHook: func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+			if s, ok := data.(string); ok {
+				return strings.ToUpper(s), nil
+			}
+			return data, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(map[string]interface{}{"name": " foo ", "count": 1}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual.Name != "FOO" || actual.Count != 1 {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	expected := []string{"to string", "from string string"}
+	if !reflect.DeepEqual(expected, calls) {
+		t.Fatalf("expected calls %#v, got %#v", expected, calls)
+	}
+}
@@ -0,0 +1,189 @@
+// Package gentest holds struct types with methods written by
+// mapstructure-gen, used to check that the generated code behaves like the
+// reflection based Decoder.
+package gentest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//go:generate go run ../../cmd/mapstructure-gen -output fixtures_mapstructure.go -type Basic,BasicPointer,BasicSquash,Embedded,EmbeddedPointer,EmbeddedSquash,EmbeddedPointerSquash,BasicMapStructure,NestedPointerWithMapstructure,EmbeddedPointerSquashWithNestedMapstructure,EmbeddedAndNamed,EmbeddedSlice,Map,MapOfStruct,Nested,NestedPointer,Slice,SliceOfStruct,Remainder,StructWithOmitEmpty,StructWithOmitZero,Unexported,TupleField
+//go:generate go run ../../cmd/mapstructure-gen -output squash_mapstructure.go -squash -type SquashedEmbedded
+
+type Basic struct {
+	Vstring     string
+	Vint        int
+	Vint8       int8
+	Vint16      int16
+	Vint32      int32
+	Vint64      int64
+	Vuint       uint
+	Vbool       bool
+	Vfloat      float64
+	Vextra      string
+	vsilent     bool
+	Vdata       interface{}
+	VjsonInt    int
+	VjsonUint   uint
+	VjsonUint64 uint64
+	VjsonFloat  float64
+	VjsonNumber json.Number
+	Vcomplex64  complex64
+	Vcomplex128 complex128
+}
+
+type BasicPointer struct {
+	Vstring     *string
+	Vint        *int
+	Vuint       *uint
+	Vbool       *bool
+	Vfloat      *float64
+	Vextra      *string
+	vsilent     *bool
+	Vdata       *interface{}
+	VjsonInt    *int
+	VjsonFloat  *float64
+	VjsonNumber *json.Number
+}
+
+type BasicSquash struct {
+	Test Basic `mapstructure:",squash"`
+}
+
+type Embedded struct {
+	Basic
+	Vunique string
+}
+
+type EmbeddedPointer struct {
+	*Basic
+	Vunique string
+}
+
+type EmbeddedSquash struct {
+	Basic   `mapstructure:",squash"`
+	Vunique string
+}
+
+type EmbeddedPointerSquash struct {
+	*Basic  `mapstructure:",squash"`
+	Vunique string
+}
+
+type BasicMapStructure struct {
+	Vunique string     `mapstructure:"vunique"`
+	Vtime   *time.Time `mapstructure:"time"`
+}
+
+type NestedPointerWithMapstructure struct {
+	Vbar *BasicMapStructure `mapstructure:"vbar"`
+}
+
+type EmbeddedPointerSquashWithNestedMapstructure struct {
+	*NestedPointerWithMapstructure `mapstructure:",squash"`
+	Vunique                        string
+}
+
+type EmbeddedAndNamed struct {
+	Basic
+	Named   Basic
+	Vunique string
+}
+
+type SliceAlias []string
+
+type EmbeddedSlice struct {
+	SliceAlias `mapstructure:"slice_alias"`
+	Vunique    string
+}
+
+type Map struct {
+	Vfoo   string
+	Vother map[string]string
+}
+
+type MapOfStruct struct {
+	Value map[string]Basic
+}
+
+type Nested struct {
+	Vfoo string
+	Vbar Basic
+}
+
+type NestedPointer struct {
+	Vfoo string
+	Vbar *Basic
+}
+
+type Slice struct {
+	Vfoo string
+	Vbar []string
+}
+
+type SliceOfStruct struct {
+	Value []Basic
+}
+
+type Remainder struct {
+	A     string
+	Extra map[string]interface{} `mapstructure:",remain"`
+}
+
+type StructWithOmitEmpty struct {
+	VisibleStringField string                 `mapstructure:"visible-string"`
+	OmitStringField    string                 `mapstructure:"omittable-string,omitempty"`
+	VisibleIntField    int                    `mapstructure:"visible-int"`
+	OmitIntField       int                    `mapstructure:"omittable-int,omitempty"`
+	VisibleFloatField  float64                `mapstructure:"visible-float"`
+	OmitFloatField     float64                `mapstructure:"omittable-float,omitempty"`
+	VisibleSliceField  []interface{}          `mapstructure:"visible-slice"`
+	OmitSliceField     []interface{}          `mapstructure:"omittable-slice,omitempty"`
+	VisibleMapField    map[string]interface{} `mapstructure:"visible-map"`
+	OmitMapField       map[string]interface{} `mapstructure:"omittable-map,omitempty"`
+	NestedField        *Nested                `mapstructure:"visible-nested"`
+	OmitNestedField    *Nested                `mapstructure:"omittable-nested,omitempty"`
+}
+
+type StructWithOmitZero struct {
+	VisibleStringField string                 `mapstructure:"visible-string"`
+	OmitStringField    string                 `mapstructure:"omittable-string,omitzero"`
+	VisibleIntField    int                    `mapstructure:"visible-int"`
+	OmitIntField       int                    `mapstructure:"omittable-int,omitzero"`
+	VisibleFloatField  float64                `mapstructure:"visible-float"`
+	OmitFloatField     float64                `mapstructure:"omittable-float,omitzero"`
+	VisibleSliceField  []interface{}          `mapstructure:"visible-slice"`
+	OmitSliceField     []interface{}          `mapstructure:"omittable-slice,omitzero"`
+	VisibleMapField    map[string]interface{} `mapstructure:"visible-map"`
+	OmitMapField       map[string]interface{} `mapstructure:"omittable-map,omitzero"`
+	NestedField        *Nested                `mapstructure:"visible-nested"`
+	OmitNestedField    *Nested                `mapstructure:"omittable-nested,omitzero"`
+}
+
+type Unexported struct {
+	Name    string `mapstructure:"name"`
+	Skipped string `mapstructure:"-"`
+	hidden  string
+	Ptr     *int `mapstructure:"ptr"`
+}
+
+type SquashedEmbedded struct {
+	Basic
+	Nested  `mapstructure:"nested"`
+	Vunique string
+}
+
+// Endpoint is decoded from a tuple. It has no generated methods, since
+// mapstructure-gen doesn't support tuples, but structs with generated
+// methods can have fields of its type.
+type Endpoint struct {
+	_    struct{} `mapstructure:",tuple"`
+	Host string
+	Port int
+}
+
+type TupleField struct {
+	Ep  Endpoint  `mapstructure:"ep"`
+	Ptr *Endpoint `mapstructure:"ptr"`
+}
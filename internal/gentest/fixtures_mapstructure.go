@@ -0,0 +1,2687 @@
+// Code generated by mapstructure-gen; DO NOT EDIT.
+
+package gentest
+
+import "github.com/CoverWhale/mapstructure/v2"
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Basic) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Basic) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Basic) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Basic) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vstring = x
+		} else if err := g.DecodeField(prefix+"Vstring", v, &t.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vstring")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Vint = x
+		} else if err := g.DecodeField(prefix+"Vint", v, &t.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint8"); ok {
+		used = append(used, k)
+		if x, ok := v.(int8); ok {
+			t.Vint8 = x
+		} else if err := g.DecodeField(prefix+"Vint8", v, &t.Vint8); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint8")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint16"); ok {
+		used = append(used, k)
+		if x, ok := v.(int16); ok {
+			t.Vint16 = x
+		} else if err := g.DecodeField(prefix+"Vint16", v, &t.Vint16); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint16")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint32"); ok {
+		used = append(used, k)
+		if x, ok := v.(int32); ok {
+			t.Vint32 = x
+		} else if err := g.DecodeField(prefix+"Vint32", v, &t.Vint32); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint32")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(int64); ok {
+			t.Vint64 = x
+		} else if err := g.DecodeField(prefix+"Vint64", v, &t.Vint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Vuint = x
+		} else if err := g.DecodeField(prefix+"Vuint", v, &t.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vuint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if x, ok := v.(bool); ok {
+			t.Vbool = x
+		} else if err := g.DecodeField(prefix+"Vbool", v, &t.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbool")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Vfloat = x
+		} else if err := g.DecodeField(prefix+"Vfloat", v, &t.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vextra = x
+		} else if err := g.DecodeField(prefix+"Vextra", v, &t.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vextra")
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = append(unset, "vsilent")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.VjsonInt = x
+		} else if err := g.DecodeField(prefix+"VjsonInt", v, &t.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonInt")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.VjsonUint = x
+		} else if err := g.DecodeField(prefix+"VjsonUint", v, &t.VjsonUint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint64); ok {
+			t.VjsonUint64 = x
+		} else if err := g.DecodeField(prefix+"VjsonUint64", v, &t.VjsonUint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.VjsonFloat = x
+		} else if err := g.DecodeField(prefix+"VjsonFloat", v, &t.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonFloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.VjsonNumber)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex64"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex64", v, &t.Vcomplex64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex64", &t.Vcomplex64)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex128"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex128", v, &t.Vcomplex128); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex128", &t.Vcomplex128)
+	}
+
+	return g.Finish(name, input, []string{"Vstring", "Vint", "Vint8", "Vint16", "Vint32", "Vint64", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonUint", "VjsonUint64", "VjsonFloat", "VjsonNumber", "Vcomplex64", "Vcomplex128"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Basic) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 19)
+	var errs []error
+
+	m["Vstring"] = t.Vstring
+
+	m["Vint"] = t.Vint
+
+	m["Vint8"] = t.Vint8
+
+	m["Vint16"] = t.Vint16
+
+	m["Vint32"] = t.Vint32
+
+	m["Vint64"] = t.Vint64
+
+	m["Vuint"] = t.Vuint
+
+	m["Vbool"] = t.Vbool
+
+	m["Vfloat"] = t.Vfloat
+
+	m["Vextra"] = t.Vextra
+
+	if v, ok, err := g.EncodeField("Vdata", &t.Vdata, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vdata"] = v
+	}
+
+	m["VjsonInt"] = t.VjsonInt
+
+	m["VjsonUint"] = t.VjsonUint
+
+	m["VjsonUint64"] = t.VjsonUint64
+
+	m["VjsonFloat"] = t.VjsonFloat
+
+	if v, ok, err := g.EncodeField("VjsonNumber", &t.VjsonNumber, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["VjsonNumber"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vcomplex64", &t.Vcomplex64, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vcomplex64"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vcomplex128", &t.Vcomplex128, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vcomplex128"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (BasicPointer) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *BasicPointer) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t BasicPointer) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *BasicPointer) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vstring", v, &t.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vstring", &t.Vstring)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vint", v, &t.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vint", &t.Vint)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vuint", v, &t.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vuint", &t.Vuint)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vbool", v, &t.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vbool", &t.Vbool)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vfloat", v, &t.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vfloat", &t.Vfloat)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vextra", v, &t.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vextra", &t.Vextra)
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = g.Unset(unset, "vsilent", &t.vsilent)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonInt", v, &t.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonInt", &t.VjsonInt)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonFloat", v, &t.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonFloat", &t.VjsonFloat)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.VjsonNumber)
+	}
+
+	return g.Finish(name, input, []string{"Vstring", "Vint", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonFloat", "VjsonNumber"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t BasicPointer) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 11)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Vstring", &t.Vstring, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vstring"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vint", &t.Vint, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vint"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vuint", &t.Vuint, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vuint"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vbool", &t.Vbool, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vbool"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vfloat", &t.Vfloat, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vfloat"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vextra", &t.Vextra, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vextra"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Vdata", &t.Vdata, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vdata"] = v
+	}
+
+	if v, ok, err := g.EncodeField("VjsonInt", &t.VjsonInt, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["VjsonInt"] = v
+	}
+
+	if v, ok, err := g.EncodeField("VjsonFloat", &t.VjsonFloat, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["VjsonFloat"] = v
+	}
+
+	if v, ok, err := g.EncodeField("VjsonNumber", &t.VjsonNumber, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["VjsonNumber"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (BasicSquash) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *BasicSquash) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t BasicSquash) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *BasicSquash) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Test.Vstring = x
+		} else if err := g.DecodeField(prefix+"Vstring", v, &t.Test.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vstring")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Test.Vint = x
+		} else if err := g.DecodeField(prefix+"Vint", v, &t.Test.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint8"); ok {
+		used = append(used, k)
+		if x, ok := v.(int8); ok {
+			t.Test.Vint8 = x
+		} else if err := g.DecodeField(prefix+"Vint8", v, &t.Test.Vint8); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint8")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint16"); ok {
+		used = append(used, k)
+		if x, ok := v.(int16); ok {
+			t.Test.Vint16 = x
+		} else if err := g.DecodeField(prefix+"Vint16", v, &t.Test.Vint16); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint16")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint32"); ok {
+		used = append(used, k)
+		if x, ok := v.(int32); ok {
+			t.Test.Vint32 = x
+		} else if err := g.DecodeField(prefix+"Vint32", v, &t.Test.Vint32); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint32")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(int64); ok {
+			t.Test.Vint64 = x
+		} else if err := g.DecodeField(prefix+"Vint64", v, &t.Test.Vint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Test.Vuint = x
+		} else if err := g.DecodeField(prefix+"Vuint", v, &t.Test.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vuint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if x, ok := v.(bool); ok {
+			t.Test.Vbool = x
+		} else if err := g.DecodeField(prefix+"Vbool", v, &t.Test.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbool")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Test.Vfloat = x
+		} else if err := g.DecodeField(prefix+"Vfloat", v, &t.Test.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Test.Vextra = x
+		} else if err := g.DecodeField(prefix+"Vextra", v, &t.Test.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vextra")
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = append(unset, "vsilent")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Test.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Test.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Test.VjsonInt = x
+		} else if err := g.DecodeField(prefix+"VjsonInt", v, &t.Test.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonInt")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Test.VjsonUint = x
+		} else if err := g.DecodeField(prefix+"VjsonUint", v, &t.Test.VjsonUint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint64); ok {
+			t.Test.VjsonUint64 = x
+		} else if err := g.DecodeField(prefix+"VjsonUint64", v, &t.Test.VjsonUint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Test.VjsonFloat = x
+		} else if err := g.DecodeField(prefix+"VjsonFloat", v, &t.Test.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonFloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.Test.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.Test.VjsonNumber)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex64"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex64", v, &t.Test.Vcomplex64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex64", &t.Test.Vcomplex64)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex128"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex128", v, &t.Test.Vcomplex128); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex128", &t.Test.Vcomplex128)
+	}
+
+	return g.Finish(name, input, []string{"Vstring", "Vint", "Vint8", "Vint16", "Vint32", "Vint64", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonUint", "VjsonUint64", "VjsonFloat", "VjsonNumber", "Vcomplex64", "Vcomplex128"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t BasicSquash) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 1)
+	var errs []error
+
+	if err := g.EncodeSquash(name, "Test", "Test", &t.Test, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Embedded) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Embedded) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Embedded) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Embedded) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Basic"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Basic", v, &t.Basic); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Basic")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	return g.Finish(name, input, []string{"Basic", "Vunique"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Embedded) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Basic", &t.Basic, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Basic"] = v
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedPointer) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedPointer) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedPointer) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedPointer) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Basic"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Basic", v, &t.Basic); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Basic", &t.Basic)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	return g.Finish(name, input, []string{"Basic", "Vunique"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedPointer) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Basic", &t.Basic, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Basic"] = v
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedSquash) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedSquash) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedSquash) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedSquash) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vstring = x
+		} else if err := g.DecodeField(prefix+"Vstring", v, &t.Basic.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vstring")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.Vint = x
+		} else if err := g.DecodeField(prefix+"Vint", v, &t.Basic.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint8"); ok {
+		used = append(used, k)
+		if x, ok := v.(int8); ok {
+			t.Basic.Vint8 = x
+		} else if err := g.DecodeField(prefix+"Vint8", v, &t.Basic.Vint8); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint8")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint16"); ok {
+		used = append(used, k)
+		if x, ok := v.(int16); ok {
+			t.Basic.Vint16 = x
+		} else if err := g.DecodeField(prefix+"Vint16", v, &t.Basic.Vint16); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint16")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint32"); ok {
+		used = append(used, k)
+		if x, ok := v.(int32); ok {
+			t.Basic.Vint32 = x
+		} else if err := g.DecodeField(prefix+"Vint32", v, &t.Basic.Vint32); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint32")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(int64); ok {
+			t.Basic.Vint64 = x
+		} else if err := g.DecodeField(prefix+"Vint64", v, &t.Basic.Vint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.Vuint = x
+		} else if err := g.DecodeField(prefix+"Vuint", v, &t.Basic.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vuint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if x, ok := v.(bool); ok {
+			t.Basic.Vbool = x
+		} else if err := g.DecodeField(prefix+"Vbool", v, &t.Basic.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbool")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.Vfloat = x
+		} else if err := g.DecodeField(prefix+"Vfloat", v, &t.Basic.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vextra = x
+		} else if err := g.DecodeField(prefix+"Vextra", v, &t.Basic.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vextra")
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = append(unset, "vsilent")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Basic.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Basic.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.VjsonInt = x
+		} else if err := g.DecodeField(prefix+"VjsonInt", v, &t.Basic.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonInt")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.VjsonUint = x
+		} else if err := g.DecodeField(prefix+"VjsonUint", v, &t.Basic.VjsonUint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint64); ok {
+			t.Basic.VjsonUint64 = x
+		} else if err := g.DecodeField(prefix+"VjsonUint64", v, &t.Basic.VjsonUint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.VjsonFloat = x
+		} else if err := g.DecodeField(prefix+"VjsonFloat", v, &t.Basic.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonFloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.Basic.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.Basic.VjsonNumber)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex64"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex64", v, &t.Basic.Vcomplex64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex64", &t.Basic.Vcomplex64)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex128"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex128", v, &t.Basic.Vcomplex128); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex128", &t.Basic.Vcomplex128)
+	}
+
+	return g.Finish(name, input, []string{"Vunique", "Vstring", "Vint", "Vint8", "Vint16", "Vint32", "Vint64", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonUint", "VjsonUint64", "VjsonFloat", "VjsonNumber", "Vcomplex64", "Vcomplex128"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedSquash) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if err := g.EncodeSquash(name, "Basic", "Basic", &t.Basic, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedPointerSquash) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedPointerSquash) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedPointerSquash) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedPointerSquash) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if t.Basic == nil {
+		t.Basic = new(Basic)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vstring = x
+		} else if err := g.DecodeField(prefix+"Vstring", v, &t.Basic.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vstring")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.Vint = x
+		} else if err := g.DecodeField(prefix+"Vint", v, &t.Basic.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint8"); ok {
+		used = append(used, k)
+		if x, ok := v.(int8); ok {
+			t.Basic.Vint8 = x
+		} else if err := g.DecodeField(prefix+"Vint8", v, &t.Basic.Vint8); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint8")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint16"); ok {
+		used = append(used, k)
+		if x, ok := v.(int16); ok {
+			t.Basic.Vint16 = x
+		} else if err := g.DecodeField(prefix+"Vint16", v, &t.Basic.Vint16); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint16")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint32"); ok {
+		used = append(used, k)
+		if x, ok := v.(int32); ok {
+			t.Basic.Vint32 = x
+		} else if err := g.DecodeField(prefix+"Vint32", v, &t.Basic.Vint32); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint32")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(int64); ok {
+			t.Basic.Vint64 = x
+		} else if err := g.DecodeField(prefix+"Vint64", v, &t.Basic.Vint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.Vuint = x
+		} else if err := g.DecodeField(prefix+"Vuint", v, &t.Basic.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vuint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if x, ok := v.(bool); ok {
+			t.Basic.Vbool = x
+		} else if err := g.DecodeField(prefix+"Vbool", v, &t.Basic.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbool")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.Vfloat = x
+		} else if err := g.DecodeField(prefix+"Vfloat", v, &t.Basic.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vextra = x
+		} else if err := g.DecodeField(prefix+"Vextra", v, &t.Basic.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vextra")
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = append(unset, "vsilent")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Basic.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Basic.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.VjsonInt = x
+		} else if err := g.DecodeField(prefix+"VjsonInt", v, &t.Basic.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonInt")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.VjsonUint = x
+		} else if err := g.DecodeField(prefix+"VjsonUint", v, &t.Basic.VjsonUint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint64); ok {
+			t.Basic.VjsonUint64 = x
+		} else if err := g.DecodeField(prefix+"VjsonUint64", v, &t.Basic.VjsonUint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.VjsonFloat = x
+		} else if err := g.DecodeField(prefix+"VjsonFloat", v, &t.Basic.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonFloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.Basic.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.Basic.VjsonNumber)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex64"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex64", v, &t.Basic.Vcomplex64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex64", &t.Basic.Vcomplex64)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex128"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex128", v, &t.Basic.Vcomplex128); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex128", &t.Basic.Vcomplex128)
+	}
+
+	return g.Finish(name, input, []string{"Vunique", "Vstring", "Vint", "Vint8", "Vint16", "Vint32", "Vint64", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonUint", "VjsonUint64", "VjsonFloat", "VjsonNumber", "Vcomplex64", "Vcomplex128"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedPointerSquash) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if err := g.EncodeSquash(name, "Basic", "Basic", &t.Basic, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (BasicMapStructure) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *BasicMapStructure) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t BasicMapStructure) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *BasicMapStructure) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "vunique")
+	}
+
+	if k, v, ok := g.Lookup(input, "time"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"time", v, &t.Vtime); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "time", &t.Vtime)
+	}
+
+	return g.Finish(name, input, []string{"vunique", "time"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t BasicMapStructure) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["vunique"] = t.Vunique
+
+	if v, ok, err := g.EncodeField("time", &t.Vtime, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["time"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (NestedPointerWithMapstructure) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *NestedPointerWithMapstructure) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t NestedPointerWithMapstructure) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *NestedPointerWithMapstructure) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"vbar", v, &t.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "vbar", &t.Vbar)
+	}
+
+	return g.Finish(name, input, []string{"vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t NestedPointerWithMapstructure) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 1)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("vbar", &t.Vbar, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["vbar"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedPointerSquashWithNestedMapstructure) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedPointerSquashWithNestedMapstructure) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedPointerSquashWithNestedMapstructure) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedPointerSquashWithNestedMapstructure) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if t.NestedPointerWithMapstructure == nil {
+		t.NestedPointerWithMapstructure = new(NestedPointerWithMapstructure)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	if k, v, ok := g.Lookup(input, "vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"vbar", v, &t.NestedPointerWithMapstructure.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "vbar", &t.NestedPointerWithMapstructure.Vbar)
+	}
+
+	return g.Finish(name, input, []string{"Vunique", "vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedPointerSquashWithNestedMapstructure) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if err := g.EncodeSquash(name, "NestedPointerWithMapstructure", "NestedPointerWithMapstructure", &t.NestedPointerWithMapstructure, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedAndNamed) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedAndNamed) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedAndNamed) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedAndNamed) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Basic"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Basic", v, &t.Basic); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Basic")
+	}
+
+	if k, v, ok := g.Lookup(input, "Named"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Named", v, &t.Named); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Named")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	return g.Finish(name, input, []string{"Basic", "Named", "Vunique"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedAndNamed) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 3)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Basic", &t.Basic, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Basic"] = v
+	}
+
+	if v, ok, err := g.EncodeField("Named", &t.Named, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Named"] = v
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (EmbeddedSlice) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *EmbeddedSlice) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t EmbeddedSlice) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *EmbeddedSlice) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "slice_alias"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"slice_alias", v, &t.SliceAlias); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "slice_alias", &t.SliceAlias)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	return g.Finish(name, input, []string{"slice_alias", "Vunique"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t EmbeddedSlice) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("slice_alias", &t.SliceAlias, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["slice_alias"] = v
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Map) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Map) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Map) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Map) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfoo"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vfoo = x
+		} else if err := g.DecodeField(prefix+"Vfoo", v, &t.Vfoo); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfoo")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vother"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vother", v, &t.Vother); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vother", &t.Vother)
+	}
+
+	return g.Finish(name, input, []string{"Vfoo", "Vother"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Map) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["Vfoo"] = t.Vfoo
+
+	if v, ok, err := g.EncodeField("Vother", &t.Vother, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vother"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (MapOfStruct) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *MapOfStruct) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t MapOfStruct) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *MapOfStruct) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Value"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Value", v, &t.Value); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Value", &t.Value)
+	}
+
+	return g.Finish(name, input, []string{"Value"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t MapOfStruct) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 1)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Value", &t.Value, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Value"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Nested) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Nested) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Nested) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Nested) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfoo"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vfoo = x
+		} else if err := g.DecodeField(prefix+"Vfoo", v, &t.Vfoo); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfoo")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vbar", v, &t.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbar")
+	}
+
+	return g.Finish(name, input, []string{"Vfoo", "Vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Nested) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["Vfoo"] = t.Vfoo
+
+	if v, ok, err := g.EncodeField("Vbar", &t.Vbar, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vbar"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (NestedPointer) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *NestedPointer) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t NestedPointer) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *NestedPointer) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfoo"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vfoo = x
+		} else if err := g.DecodeField(prefix+"Vfoo", v, &t.Vfoo); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfoo")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vbar", v, &t.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vbar", &t.Vbar)
+	}
+
+	return g.Finish(name, input, []string{"Vfoo", "Vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t NestedPointer) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["Vfoo"] = t.Vfoo
+
+	if v, ok, err := g.EncodeField("Vbar", &t.Vbar, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vbar"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Slice) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Slice) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Slice) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Slice) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfoo"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vfoo = x
+		} else if err := g.DecodeField(prefix+"Vfoo", v, &t.Vfoo); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfoo")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vbar", v, &t.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vbar", &t.Vbar)
+	}
+
+	return g.Finish(name, input, []string{"Vfoo", "Vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Slice) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["Vfoo"] = t.Vfoo
+
+	if v, ok, err := g.EncodeField("Vbar", &t.Vbar, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Vbar"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (SliceOfStruct) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *SliceOfStruct) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t SliceOfStruct) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *SliceOfStruct) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Value"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Value", v, &t.Value); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Value", &t.Value)
+	}
+
+	return g.Finish(name, input, []string{"Value"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t SliceOfStruct) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 1)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("Value", &t.Value, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["Value"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Remainder) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Remainder) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Remainder) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Remainder) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "A"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.A = x
+		} else if err := g.DecodeField(prefix+"A", v, &t.A); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "A")
+	}
+
+	return g.Finish(name, input, []string{"A"}, used, unset, &t.Extra, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Remainder) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	m["A"] = t.A
+
+	for k, v := range t.Extra {
+		m[k] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (StructWithOmitEmpty) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *StructWithOmitEmpty) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t StructWithOmitEmpty) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *StructWithOmitEmpty) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-string"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.VisibleStringField = x
+		} else if err := g.DecodeField(prefix+"visible-string", v, &t.VisibleStringField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-string")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-string"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.OmitStringField = x
+		} else if err := g.DecodeField(prefix+"omittable-string", v, &t.OmitStringField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-string")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-int"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.VisibleIntField = x
+		} else if err := g.DecodeField(prefix+"visible-int", v, &t.VisibleIntField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-int")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-int"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.OmitIntField = x
+		} else if err := g.DecodeField(prefix+"omittable-int", v, &t.OmitIntField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-int")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-float"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.VisibleFloatField = x
+		} else if err := g.DecodeField(prefix+"visible-float", v, &t.VisibleFloatField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-float")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-float"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.OmitFloatField = x
+		} else if err := g.DecodeField(prefix+"omittable-float", v, &t.OmitFloatField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-float")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-slice"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-slice", v, &t.VisibleSliceField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-slice", &t.VisibleSliceField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-slice"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-slice", v, &t.OmitSliceField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-slice", &t.OmitSliceField)
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-map"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-map", v, &t.VisibleMapField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-map", &t.VisibleMapField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-map"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-map", v, &t.OmitMapField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-map", &t.OmitMapField)
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-nested"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-nested", v, &t.NestedField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-nested", &t.NestedField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-nested"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-nested", v, &t.OmitNestedField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-nested", &t.OmitNestedField)
+	}
+
+	return g.Finish(name, input, []string{"visible-string", "omittable-string", "visible-int", "omittable-int", "visible-float", "omittable-float", "visible-slice", "omittable-slice", "visible-map", "omittable-map", "visible-nested", "omittable-nested"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t StructWithOmitEmpty) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 12)
+	var errs []error
+
+	m["visible-string"] = t.VisibleStringField
+
+	if t.OmitStringField != "" {
+		m["omittable-string"] = t.OmitStringField
+	}
+
+	m["visible-int"] = t.VisibleIntField
+
+	if t.OmitIntField != 0 {
+		m["omittable-int"] = t.OmitIntField
+	}
+
+	m["visible-float"] = t.VisibleFloatField
+
+	if v, ok, err := g.EncodeField("omittable-float", &t.OmitFloatField, true, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-float"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-slice", &t.VisibleSliceField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-slice"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-slice", &t.OmitSliceField, true, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-slice"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-map", &t.VisibleMapField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-map"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-map", &t.OmitMapField, true, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-map"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-nested", &t.NestedField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-nested"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-nested", &t.OmitNestedField, true, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-nested"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (StructWithOmitZero) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *StructWithOmitZero) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t StructWithOmitZero) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *StructWithOmitZero) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-string"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.VisibleStringField = x
+		} else if err := g.DecodeField(prefix+"visible-string", v, &t.VisibleStringField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-string")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-string"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.OmitStringField = x
+		} else if err := g.DecodeField(prefix+"omittable-string", v, &t.OmitStringField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-string")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-int"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.VisibleIntField = x
+		} else if err := g.DecodeField(prefix+"visible-int", v, &t.VisibleIntField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-int")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-int"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.OmitIntField = x
+		} else if err := g.DecodeField(prefix+"omittable-int", v, &t.OmitIntField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-int")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-float"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.VisibleFloatField = x
+		} else if err := g.DecodeField(prefix+"visible-float", v, &t.VisibleFloatField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "visible-float")
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-float"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.OmitFloatField = x
+		} else if err := g.DecodeField(prefix+"omittable-float", v, &t.OmitFloatField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "omittable-float")
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-slice"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-slice", v, &t.VisibleSliceField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-slice", &t.VisibleSliceField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-slice"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-slice", v, &t.OmitSliceField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-slice", &t.OmitSliceField)
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-map"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-map", v, &t.VisibleMapField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-map", &t.VisibleMapField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-map"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-map", v, &t.OmitMapField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-map", &t.OmitMapField)
+	}
+
+	if k, v, ok := g.Lookup(input, "visible-nested"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"visible-nested", v, &t.NestedField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "visible-nested", &t.NestedField)
+	}
+
+	if k, v, ok := g.Lookup(input, "omittable-nested"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"omittable-nested", v, &t.OmitNestedField); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "omittable-nested", &t.OmitNestedField)
+	}
+
+	return g.Finish(name, input, []string{"visible-string", "omittable-string", "visible-int", "omittable-int", "visible-float", "omittable-float", "visible-slice", "omittable-slice", "visible-map", "omittable-map", "visible-nested", "omittable-nested"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t StructWithOmitZero) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 12)
+	var errs []error
+
+	m["visible-string"] = t.VisibleStringField
+
+	if t.OmitStringField != "" {
+		m["omittable-string"] = t.OmitStringField
+	}
+
+	m["visible-int"] = t.VisibleIntField
+
+	if t.OmitIntField != 0 {
+		m["omittable-int"] = t.OmitIntField
+	}
+
+	m["visible-float"] = t.VisibleFloatField
+
+	if v, ok, err := g.EncodeField("omittable-float", &t.OmitFloatField, false, true); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-float"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-slice", &t.VisibleSliceField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-slice"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-slice", &t.OmitSliceField, false, true); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-slice"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-map", &t.VisibleMapField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-map"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-map", &t.OmitMapField, false, true); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-map"] = v
+	}
+
+	if v, ok, err := g.EncodeField("visible-nested", &t.NestedField, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["visible-nested"] = v
+	}
+
+	if v, ok, err := g.EncodeField("omittable-nested", &t.OmitNestedField, false, true); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["omittable-nested"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (Unexported) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *Unexported) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t Unexported) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *Unexported) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "name"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Name = x
+		} else if err := g.DecodeField(prefix+"name", v, &t.Name); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "name")
+	}
+
+	if k, v, ok := g.Lookup(input, "-"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Skipped = x
+		} else if err := g.DecodeField(prefix+"-", v, &t.Skipped); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "-")
+	}
+
+	if _, _, ok := g.Lookup(input, "hidden"); !ok {
+		unset = append(unset, "hidden")
+	}
+
+	if k, v, ok := g.Lookup(input, "ptr"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"ptr", v, &t.Ptr); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "ptr", &t.Ptr)
+	}
+
+	return g.Finish(name, input, []string{"name", "ptr"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t Unexported) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 4)
+	var errs []error
+
+	m["name"] = t.Name
+
+	if v, ok, err := g.EncodeField("ptr", &t.Ptr, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["ptr"] = v
+	}
+
+	return m, g.Join(errs)
+}
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (TupleField) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: false, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *TupleField) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t TupleField) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *TupleField) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "ep"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"ep", v, &t.Ep); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "ep")
+	}
+
+	if k, v, ok := g.Lookup(input, "ptr"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"ptr", v, &t.Ptr); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "ptr", &t.Ptr)
+	}
+
+	return g.Finish(name, input, []string{"ep", "ptr"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t TupleField) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 2)
+	var errs []error
+
+	if v, ok, err := g.EncodeField("ep", &t.Ep, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["ep"] = v
+	}
+
+	if v, ok, err := g.EncodeField("ptr", &t.Ptr, false, false); err != nil {
+		errs = append(errs, err)
+	} else if ok {
+		m["ptr"] = v
+	}
+
+	return m, g.Join(errs)
+}
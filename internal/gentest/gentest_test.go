@@ -0,0 +1,357 @@
+package gentest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/CoverWhale/mapstructure/v2"
+)
+
+var fixtures = []func() interface{}{
+	func() interface{} { return new(Basic) },
+	func() interface{} { return new(BasicPointer) },
+	func() interface{} { return new(BasicSquash) },
+	func() interface{} { return new(Embedded) },
+	func() interface{} { return new(EmbeddedPointer) },
+	func() interface{} { return new(EmbeddedSquash) },
+	func() interface{} { return new(EmbeddedPointerSquash) },
+	func() interface{} { return new(BasicMapStructure) },
+	func() interface{} { return new(NestedPointerWithMapstructure) },
+	func() interface{} { return new(EmbeddedPointerSquashWithNestedMapstructure) },
+	func() interface{} { return new(EmbeddedAndNamed) },
+	func() interface{} { return new(EmbeddedSlice) },
+	func() interface{} { return new(Map) },
+	func() interface{} { return new(MapOfStruct) },
+	func() interface{} { return new(Nested) },
+	func() interface{} { return new(NestedPointer) },
+	func() interface{} { return new(Slice) },
+	func() interface{} { return new(SliceOfStruct) },
+	func() interface{} { return new(Remainder) },
+	func() interface{} { return new(StructWithOmitEmpty) },
+	func() interface{} { return new(StructWithOmitZero) },
+	func() interface{} { return new(Unexported) },
+	func() interface{} { return new(TupleField) },
+	func() interface{} { return new(SquashedEmbedded) },
+}
+
+var inputs = []map[string]interface{}{
+	{},
+	{
+		"vstring":     "foo",
+		"Vint":        42,
+		"vint8":       int8(8),
+		"Vint16":      int16(16),
+		"Vuint":       uint(42),
+		"vbool":       true,
+		"Vfloat":      42.42,
+		"Vextra":      "extra",
+		"vsilent":     true,
+		"vdata":       42,
+		"vjsonInt":    json.Number("1234"),
+		"vjsonUint":   json.Number("1234"),
+		"vjsonFloat":  json.Number("1234.5"),
+		"vjsonNumber": json.Number("1234.5"),
+		"Vcomplex64":  complex64(1 + 2i),
+		"Vunique":     "unique",
+		"Vfoo":        "foo",
+		"A":           "a",
+		"name":        "name",
+		"ptr":         7,
+		"ep":          []interface{}{"localhost", 80},
+	},
+	{
+		"Vstring": 42,
+		"Vint":    "42",
+		"Vuint":   "7",
+		"Vbool":   "1",
+		"Vfloat":  "1.5",
+		"Vdata":   nil,
+		"Vbar":    []string{"a", "b"},
+		"Vother":  map[string]interface{}{"a": 1},
+		"-":       "dash",
+		"hidden":  "hidden",
+		"extra":   []int{1},
+		"VSTRING": "upper",
+		"ep":      []interface{}{"localhost"},
+	},
+	{
+		"Vint":   "nope",
+		"Vbool":  []int{1},
+		"Vfloat": map[string]interface{}{},
+		"Vfoo":   []int{1},
+		"Vbar": map[string]interface{}{
+			"Vstring": "nested",
+			"Vint":    "bad",
+			"unused":  true,
+		},
+		"Value": map[string]interface{}{
+			"a": map[string]interface{}{"Vint": 3, "other": 1},
+		},
+		"vbar": map[string]interface{}{
+			"vunique": "bar",
+			"time":    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		"slice_alias": []string{"a", "b"},
+		"Named":       map[string]interface{}{"Vstring": "named"},
+		"Basic":       map[string]interface{}{"Vstring": "embedded"},
+	},
+	{
+		"Value":                         []interface{}{map[string]interface{}{"Vstring": "one"}, 42},
+		"visible-string":                "visible",
+		"omittable-int":                 3,
+		"visible-slice":                 []interface{}{1, "two"},
+		"omittable-map":                 map[string]interface{}{"a": 1},
+		"visible-nested":                map[string]interface{}{"Vfoo": "nested"},
+		"time":                          "not a time",
+		"Vbar":                          [3]string{"a", "b", "c"},
+		"ptr":                           nil,
+		"Vcomplex128":                   complex(1, 2),
+		"VjsonUint64":                   uint64(1 << 63),
+		"VjsonNumber":                   "1.5",
+		"vunique":                       "lower",
+		"omittable-string":              "",
+		"omittable-float":               0.5,
+		"omittable-nested":              nil,
+		"visible-map":                   nil,
+		"omittable-slice":               []interface{}{},
+		"visible-float":                 float32(1.5),
+		"visible-int":                   int64(9),
+		"Vint32":                        int32(32),
+		"Vint64":                        int64(64),
+		"VjsonInt":                      1.0,
+		"VjsonFloat":                    1,
+		"Vextra":                        true,
+		"Vdata":                         map[string]interface{}{"a": 1},
+		"Extra":                         map[string]interface{}{"nested": true},
+		"A":                             1,
+		"NestedPointerWithMapstructure": map[string]interface{}{},
+	},
+}
+
+var configs = []mapstructure.DecoderConfig{
+	{},
+	{WeaklyTypedInput: true},
+	{ErrorUnused: true},
+	{ErrorUnset: true},
+	{ErrorUnset: true, AllowUnsetPointer: true},
+	{WeaklyTypedInput: true, ZeroFields: true, ErrorUnused: true, ErrorUnset: true},
+	{Squash: true},
+	{Squash: true, WeaklyTypedInput: true, ErrorUnused: true, ErrorUnset: true},
+	{ErrorUnused: true, IgnoreUnusedKeys: []string{"**.Vextra", "V?ar.*", "hidden"}},
+}
+
+func decode(t *testing.T, config mapstructure.DecoderConfig, input interface{}, result interface{}) error {
+	t.Helper()
+
+	config.Result = result
+	decoder, err := mapstructure.NewDecoder(&config)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return decoder.Decode(input)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+func TestGenerated_implemented(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range fixtures {
+		v := fixture()
+		if _, ok := v.(mapstructure.GeneratedDecoder); !ok {
+			t.Errorf("%T doesn't implement GeneratedDecoder", v)
+		}
+		if _, ok := reflect.ValueOf(v).Elem().Interface().(mapstructure.GeneratedEncoder); !ok {
+			t.Errorf("%T doesn't implement GeneratedEncoder", v)
+		}
+	}
+}
+
+func TestGenerated_decodeConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range fixtures {
+		for i, config := range configs {
+			name := fmt.Sprintf("%T/config%d", fixture(), i)
+			t.Run(name, func(t *testing.T) {
+				// Decoding into the same value again checks that merging
+				// into existing values matches too.
+				generated, reflective := fixture(), fixture()
+				reflectiveConfig := config
+				reflectiveConfig.IgnoreGenerated = true
+
+				for j, input := range inputs {
+					genErr := decode(t, config, input, generated)
+					refErr := decode(t, reflectiveConfig, input, reflective)
+
+					if errString(genErr) != errString(refErr) {
+						t.Fatalf("input %d: error mismatch\ngenerated:  %s\nreflective: %s", j, genErr, refErr)
+					}
+					if !reflect.DeepEqual(generated, reflective) {
+						t.Fatalf("input %d: Decode() expected: %#v\ngot: %#v", j, reflective, generated)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerated_encodeConformance(t *testing.T) {
+	t.Parallel()
+
+	for _, fixture := range fixtures {
+		for j, input := range inputs {
+			value := fixture()
+			_ = decode(t, mapstructure.DecoderConfig{WeaklyTypedInput: true}, input, value)
+
+			for _, v := range []interface{}{value, reflect.ValueOf(value).Elem().Interface()} {
+				for _, squash := range []bool{false, true} {
+					var generated, reflective map[string]interface{}
+					genErr := decode(t, mapstructure.DecoderConfig{Squash: squash}, v, &generated)
+					refErr := decode(t, mapstructure.DecoderConfig{Squash: squash, IgnoreGenerated: true}, v, &reflective)
+
+					if errString(genErr) != errString(refErr) {
+						t.Fatalf("%T input %d: error mismatch\ngenerated:  %s\nreflective: %s", value, j, genErr, refErr)
+					}
+					if !reflect.DeepEqual(generated, reflective) {
+						t.Fatalf("%T input %d: Decode() expected: %#v\ngot: %#v", value, j, reflective, generated)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerated_standalone(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"Vfoo": "foo",
+		"Vbar": map[string]interface{}{"Vstring": "bar", "Vint": 42},
+	}
+
+	var result Nested
+	if err := result.DecodeMapstructure(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected Nested
+	if err := mapstructure.Decode(input, &expected); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	var expectedMap map[string]interface{}
+	if err := mapstructure.Decode(expected, &expectedMap); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if m := result.EncodeMapstructure(); !reflect.DeepEqual(m, expectedMap) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expectedMap, m)
+	}
+
+	if err := result.DecodeMapstructure(map[string]interface{}{"Vfoo": []int{1}}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestGenerated_tupleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	value := TupleField{
+		Ep:  Endpoint{Host: "localhost", Port: 80},
+		Ptr: &Endpoint{Host: "example.com", Port: 443},
+	}
+
+	var generated, reflective map[string]interface{}
+	if err := decode(t, mapstructure.DecoderConfig{}, value, &generated); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decode(t, mapstructure.DecoderConfig{IgnoreGenerated: true}, value, &reflective); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"ep":  []interface{}{"localhost", 80},
+		"ptr": []interface{}{"example.com", 443},
+	}
+	if !reflect.DeepEqual(generated, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, generated)
+	}
+	if !reflect.DeepEqual(reflective, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, reflective)
+	}
+
+	var result TupleField
+	if err := decode(t, mapstructure.DecoderConfig{}, generated, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(result, value) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", value, result)
+	}
+}
+
+// recorder has hand-written methods in place of generated ones to check
+// when the Decoder uses them.
+type recorder struct {
+	Name   string
+	called bool
+}
+
+func (recorder) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure"}
+}
+
+func (r *recorder) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	r.called = true
+	return nil
+}
+
+func TestGenerated_detection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		config mapstructure.DecoderConfig
+		called bool
+	}{
+		{"default", mapstructure.DecoderConfig{}, true},
+		{"weak", mapstructure.DecoderConfig{WeaklyTypedInput: true, ErrorUnused: true}, true},
+		{"ignored", mapstructure.DecoderConfig{IgnoreGenerated: true}, false},
+		{"layout", mapstructure.DecoderConfig{TagName: "json"}, false},
+		{"hook", mapstructure.DecoderConfig{DecodeHook: mapstructure.StringToTimeDurationHookFunc()}, false},
+		{"path hooks", mapstructure.DecoderConfig{PathHooks: map[string]mapstructure.DecodeHookFunc{
+			"Name": mapstructure.StringToTimeDurationHookFunc(),
+		}}, false},
+		{"metadata", mapstructure.DecoderConfig{Metadata: &mapstructure.Metadata{}}, false},
+		{"fail fast", mapstructure.DecoderConfig{MaxErrors: mapstructure.FailFast}, false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result recorder
+			if err := decode(t, tc.config, map[string]interface{}{"Name": "foo"}, &result); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if result.called != tc.called {
+				t.Fatalf("expected called to be %t, got %t", tc.called, result.called)
+			}
+			if !tc.called && result.Name != "foo" {
+				t.Fatalf("expected reflective decoding, got %#v", result)
+			}
+		})
+	}
+}
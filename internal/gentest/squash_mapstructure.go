@@ -0,0 +1,278 @@
+// Code generated by mapstructure-gen; DO NOT EDIT.
+
+package gentest
+
+import "github.com/CoverWhale/mapstructure/v2"
+
+// MapstructureLayout implements mapstructure.GeneratedDecoder and
+// mapstructure.GeneratedEncoder.
+func (SquashedEmbedded) MapstructureLayout() mapstructure.GeneratedLayout {
+	return mapstructure.GeneratedLayout{TagName: "mapstructure", Squash: true, IgnoreUntaggedFields: false}
+}
+
+// DecodeMapstructure decodes input into t.
+func (t *SquashedEmbedded) DecodeMapstructure(input map[string]any) error {
+	return mapstructure.DecodeGenerated(t, input, &mapstructure.DecoderConfig{Squash: true})
+}
+
+// EncodeMapstructure returns t as a map.
+func (t SquashedEmbedded) EncodeMapstructure() map[string]any {
+	m, _ := mapstructure.EncodeGenerated(t, &mapstructure.DecoderConfig{Squash: true})
+	return m
+}
+
+// DecodeMapstructureWith implements mapstructure.GeneratedDecoder.
+func (t *SquashedEmbedded) DecodeMapstructureWith(g mapstructure.GenSupport, name string, input map[string]interface{}) error {
+	var errs []error
+	var unset []string
+	used := make([]string, 0, len(input))
+	prefix := ""
+	if name != "" {
+		prefix = name + "."
+	}
+
+	if k, v, ok := g.Lookup(input, "Vunique"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Vunique = x
+		} else if err := g.DecodeField(prefix+"Vunique", v, &t.Vunique); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vunique")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vstring"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vstring = x
+		} else if err := g.DecodeField(prefix+"Vstring", v, &t.Basic.Vstring); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vstring")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.Vint = x
+		} else if err := g.DecodeField(prefix+"Vint", v, &t.Basic.Vint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint8"); ok {
+		used = append(used, k)
+		if x, ok := v.(int8); ok {
+			t.Basic.Vint8 = x
+		} else if err := g.DecodeField(prefix+"Vint8", v, &t.Basic.Vint8); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint8")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint16"); ok {
+		used = append(used, k)
+		if x, ok := v.(int16); ok {
+			t.Basic.Vint16 = x
+		} else if err := g.DecodeField(prefix+"Vint16", v, &t.Basic.Vint16); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint16")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint32"); ok {
+		used = append(used, k)
+		if x, ok := v.(int32); ok {
+			t.Basic.Vint32 = x
+		} else if err := g.DecodeField(prefix+"Vint32", v, &t.Basic.Vint32); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint32")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(int64); ok {
+			t.Basic.Vint64 = x
+		} else if err := g.DecodeField(prefix+"Vint64", v, &t.Basic.Vint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vuint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.Vuint = x
+		} else if err := g.DecodeField(prefix+"Vuint", v, &t.Basic.Vuint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vuint")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbool"); ok {
+		used = append(used, k)
+		if x, ok := v.(bool); ok {
+			t.Basic.Vbool = x
+		} else if err := g.DecodeField(prefix+"Vbool", v, &t.Basic.Vbool); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbool")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.Vfloat = x
+		} else if err := g.DecodeField(prefix+"Vfloat", v, &t.Basic.Vfloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vextra"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Basic.Vextra = x
+		} else if err := g.DecodeField(prefix+"Vextra", v, &t.Basic.Vextra); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vextra")
+	}
+
+	if _, _, ok := g.Lookup(input, "vsilent"); !ok {
+		unset = append(unset, "vsilent")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vdata"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vdata", v, &t.Basic.Vdata); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vdata", &t.Basic.Vdata)
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonInt"); ok {
+		used = append(used, k)
+		if x, ok := v.(int); ok {
+			t.Basic.VjsonInt = x
+		} else if err := g.DecodeField(prefix+"VjsonInt", v, &t.Basic.VjsonInt); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonInt")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint); ok {
+			t.Basic.VjsonUint = x
+		} else if err := g.DecodeField(prefix+"VjsonUint", v, &t.Basic.VjsonUint); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonUint64"); ok {
+		used = append(used, k)
+		if x, ok := v.(uint64); ok {
+			t.Basic.VjsonUint64 = x
+		} else if err := g.DecodeField(prefix+"VjsonUint64", v, &t.Basic.VjsonUint64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonUint64")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonFloat"); ok {
+		used = append(used, k)
+		if x, ok := v.(float64); ok {
+			t.Basic.VjsonFloat = x
+		} else if err := g.DecodeField(prefix+"VjsonFloat", v, &t.Basic.VjsonFloat); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "VjsonFloat")
+	}
+
+	if k, v, ok := g.Lookup(input, "VjsonNumber"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"VjsonNumber", v, &t.Basic.VjsonNumber); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "VjsonNumber", &t.Basic.VjsonNumber)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex64"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex64", v, &t.Basic.Vcomplex64); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex64", &t.Basic.Vcomplex64)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vcomplex128"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vcomplex128", v, &t.Basic.Vcomplex128); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = g.Unset(unset, "Vcomplex128", &t.Basic.Vcomplex128)
+	}
+
+	if k, v, ok := g.Lookup(input, "Vfoo"); ok {
+		used = append(used, k)
+		if x, ok := v.(string); ok {
+			t.Nested.Vfoo = x
+		} else if err := g.DecodeField(prefix+"Vfoo", v, &t.Nested.Vfoo); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vfoo")
+	}
+
+	if k, v, ok := g.Lookup(input, "Vbar"); ok {
+		used = append(used, k)
+		if err := g.DecodeField(prefix+"Vbar", v, &t.Nested.Vbar); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		unset = append(unset, "Vbar")
+	}
+
+	return g.Finish(name, input, []string{"Vunique", "Vstring", "Vint", "Vint8", "Vint16", "Vint32", "Vint64", "Vuint", "Vbool", "Vfloat", "Vextra", "Vdata", "VjsonInt", "VjsonUint", "VjsonUint64", "VjsonFloat", "VjsonNumber", "Vcomplex64", "Vcomplex128", "Vfoo", "Vbar"}, used, unset, nil, errs)
+}
+
+// EncodeMapstructureWith implements mapstructure.GeneratedEncoder.
+func (t SquashedEmbedded) EncodeMapstructureWith(g mapstructure.GenSupport, name string) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, 3)
+	var errs []error
+
+	if err := g.EncodeSquash(name, "Basic", "Basic", &t.Basic, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := g.EncodeSquash(name, "Nested", "nested", &t.Nested, m); err != nil {
+		errs = append(errs, err)
+	}
+
+	m["Vunique"] = t.Vunique
+
+	return m, g.Join(errs)
+}
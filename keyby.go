@@ -0,0 +1,204 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagKeyBy returns the key set by the "keyby" option of a tag, naming the
+// key of the elements that a slice is keyed by when it is turned into a
+// map, or the other way around.
+func tagKeyBy(options []string) string {
+	for _, option := range options {
+		if strings.HasPrefix(option, "keyby=") {
+			return strings.TrimPrefix(option, "keyby=")
+		}
+	}
+
+	return ""
+}
+
+// keyByInput converts the input of a field with the "keyby" option to the
+// shape of outVal: a slice is turned into a map keyed by the keyBy key of
+// its elements, and a map into a slice of its values, sorted by key, with
+// the key set as the keyBy key of each. Any other input is returned as it
+// is.
+func (d *Decoder) keyByInput(name, keyBy string, input interface{}, outVal reflect.Value) (interface{}, error) {
+	outType := outVal.Type()
+	for outType.Kind() == reflect.Ptr {
+		outType = outType.Elem()
+	}
+
+	dataVal := reflect.Indirect(reflect.ValueOf(input))
+	if dataVal.Kind() == reflect.Interface {
+		dataVal = reflect.Indirect(dataVal.Elem())
+	}
+
+	switch {
+	case outType.Kind() == reflect.Map && (dataVal.Kind() == reflect.Slice || dataVal.Kind() == reflect.Array):
+		result := make(map[interface{}]interface{}, dataVal.Len())
+		for i := 0; i < dataVal.Len(); i++ {
+			elemName := name + "[" + fmt.Sprint(i) + "]"
+			elem := dataVal.Index(i).Interface()
+
+			fields, err := d.keyedElement(elemName, elem)
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := d.lookupElementKey(fields, keyBy)
+			if !ok {
+				return nil, newDecodeError(elemName, fmt.Errorf("has no key %q", keyBy))
+			}
+			if key == nil || !reflect.TypeOf(key).Comparable() {
+				return nil, newDecodeError(elemName, fmt.Errorf("has %s of type %T, which can't be a map key", keyBy, key))
+			}
+			if _, ok := result[key]; ok {
+				return nil, newDecodeError(elemName, fmt.Errorf("has duplicate %s %q", keyBy, fmt.Sprint(key)))
+			}
+
+			result[key] = elem
+		}
+		return result, nil
+
+	case (outType.Kind() == reflect.Slice || outType.Kind() == reflect.Array) && dataVal.Kind() == reflect.Map:
+		keys := dataVal.MapKeys()
+		sortMapKeys(keys)
+
+		result := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			elemName := name + "[" + fmt.Sprint(k.Interface()) + "]"
+
+			fields, err := d.keyedElement(elemName, dataVal.MapIndex(k).Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			if key, ok := d.lookupElementKey(fields, keyBy); ok && fmt.Sprint(key) != fmt.Sprint(k.Interface()) {
+				return nil, newDecodeError(elemName, fmt.Errorf("has %s %q, which doesn't match its key", keyBy, fmt.Sprint(key)))
+			} else if !ok {
+				fields[keyBy] = k.Interface()
+			}
+
+			result = append(result, fields)
+		}
+		return result, nil
+	}
+
+	return input, nil
+}
+
+// keyedElement returns a copy of an element of a field with the "keyby"
+// option as a map. Nil elements are empty maps, and structs are decoded
+// into maps.
+func (d *Decoder) keyedElement(name string, elem interface{}) (map[string]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(elem))
+	if v.Kind() == reflect.Interface {
+		v = reflect.Indirect(v.Elem())
+	}
+
+	result := make(map[string]interface{})
+	switch {
+	case !v.IsValid():
+		return result, nil
+
+	case v.Kind() == reflect.Map && (v.Type().Key().Kind() == reflect.String || v.Type().Key().Kind() == reflect.Interface):
+		iter := v.MapRange()
+		for iter.Next() {
+			k, ok := iter.Key().Interface().(string)
+			if !ok {
+				return nil, newDecodeError(name, fmt.Errorf("needs a map with string keys, has key %#v", iter.Key().Interface()))
+			}
+			result[k] = iter.Value().Interface()
+		}
+		return result, nil
+
+	case v.Kind() == reflect.Struct:
+		if err := d.decode(name, elem, reflect.ValueOf(&result).Elem()); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	return nil, newDecodeError(name, fmt.Errorf("expected a map or struct for keyed element, got %q", v.Kind()))
+}
+
+// lookupElementKey returns the value of the key named keyBy in the fields
+// of an element, matching the key the way struct fields are.
+func (d *Decoder) lookupElementKey(fields map[string]interface{}, keyBy string) (interface{}, bool) {
+	if v, ok := fields[keyBy]; ok {
+		return v, true
+	}
+
+	for k, v := range fields {
+		if d.config.MatchName(k, keyBy) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// keyByOutput converts the value of a field with the "keyby" option when a
+// struct is decoded into a map, the opposite way keyByInput does: a map
+// is turned into a slice of its values, sorted by key, with the key set as
+// the keyBy key of each, and a slice into a map keyed by the keyBy key of
+// its elements.
+func (d *Decoder) keyByOutput(name, keyBy string, v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+
+		keys := v.MapKeys()
+		sortMapKeys(keys)
+
+		result := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			elemName := name + "[" + fmt.Sprint(k.Interface()) + "]"
+
+			fields, err := d.keyedElement(elemName, v.MapIndex(k).Interface())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			fields[keyBy] = k.Interface()
+
+			result = append(result, fields)
+		}
+		return reflect.ValueOf(result), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v, nil
+		}
+
+		result := make(map[string]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elemName := name + "[" + fmt.Sprint(i) + "]"
+
+			fields, err := d.keyedElement(elemName, v.Index(i).Interface())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			key, ok := fields[keyBy]
+			if !ok {
+				return reflect.Value{}, newDecodeError(elemName, fmt.Errorf("has no key %q", keyBy))
+			}
+			if _, ok := result[fmt.Sprint(key)]; ok {
+				return reflect.Value{}, newDecodeError(elemName, fmt.Errorf("has duplicate %s %q", keyBy, fmt.Sprint(key)))
+			}
+
+			result[fmt.Sprint(key)] = fields
+		}
+		return reflect.ValueOf(result), nil
+	}
+
+	return v, nil
+}
@@ -0,0 +1,209 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type KeyByServer struct {
+	Name string `mapstructure:"name"`
+	Port int    `mapstructure:"port"`
+}
+
+type KeyByConfig struct {
+	Servers map[string]KeyByServer `mapstructure:"servers,keyby=name"`
+	Backups []KeyByServer          `mapstructure:"backups,keyby=name"`
+}
+
+func TestDecode_keyBy(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "port": 80},
+			map[string]interface{}{"NAME": "b", "port": 81},
+		},
+		"backups": map[string]interface{}{
+			"y": map[string]interface{}{"port": 91},
+			"x": map[string]interface{}{"name": "x", "port": 90},
+			"z": nil,
+		},
+	}
+
+	var result KeyByConfig
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnused: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := KeyByConfig{
+		Servers: map[string]KeyByServer{
+			"a": {Name: "a", Port: 80},
+			"b": {Name: "b", Port: 81},
+		},
+		Backups: []KeyByServer{
+			{Name: "x", Port: 90},
+			{Name: "y", Port: 91},
+			{Name: "z"},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_keyByErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input map[string]interface{}
+		err   string
+	}{
+		{
+			"duplicate",
+			map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"name": "a"},
+					map[string]interface{}{"name": "a"},
+				},
+			},
+			`'servers[1]' has duplicate name "a"`,
+		},
+		{
+			"missing",
+			map[string]interface{}{
+				"servers": []interface{}{map[string]interface{}{"port": 80}},
+			},
+			`'servers[0]' has no key "name"`,
+		},
+		{
+			"mismatch",
+			map[string]interface{}{
+				"backups": map[string]interface{}{"x": map[string]interface{}{"name": "y"}},
+			},
+			`'backups[x]' has name "y", which doesn't match its key`,
+		},
+		{
+			"not a map",
+			map[string]interface{}{
+				"servers": []interface{}{"a"},
+			},
+			`'servers[0]' expected a map or struct for keyed element, got "string"`,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result KeyByConfig
+			err := Decode(tc.input, &result)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDecode_keyByEncode(t *testing.T) {
+	t.Parallel()
+
+	input := KeyByConfig{
+		Servers: map[string]KeyByServer{
+			"b": {Port: 81},
+			"a": {Name: "a", Port: 80},
+		},
+		Backups: []KeyByServer{
+			{Name: "x", Port: 90},
+		},
+	}
+
+	var result map[string]interface{}
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "port": 80},
+			map[string]interface{}{"name": "b", "port": 81},
+		},
+		"backups": map[string]interface{}{
+			"x": map[string]interface{}{"name": "x", "port": 90},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// Decoding the map back restores the struct, with the keys set.
+	var roundTrip KeyByConfig
+	if err := Decode(result, &roundTrip); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	input.Servers["b"] = KeyByServer{Name: "b", Port: 81}
+	if !reflect.DeepEqual(roundTrip, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, roundTrip)
+	}
+}
+
+func TestSet_keyBy(t *testing.T) {
+	t.Parallel()
+
+	var result KeyByConfig
+	servers := []interface{}{map[string]interface{}{"name": "a", "port": 1}}
+	if err := Set(&result, "servers", servers, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	backups := map[string]interface{}{"b": map[string]interface{}{"port": 2}}
+	if err := Set(&result, "backups", backups, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := KeyByConfig{
+		Servers: map[string]KeyByServer{"a": {Name: "a", Port: 1}},
+		Backups: []KeyByServer{{Name: "b", Port: 2}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Set() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestApplyMergePatch_keyBy(t *testing.T) {
+	t.Parallel()
+
+	result := KeyByConfig{
+		Servers: map[string]KeyByServer{"old": {Name: "old", Port: 9}},
+	}
+	patch := map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{"name": "a", "port": 1}},
+		"backups": map[string]interface{}{"b": map[string]interface{}{"port": 2}},
+	}
+
+	changed, err := ApplyMergePatch(&result, patch, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := KeyByConfig{
+		Servers: map[string]KeyByServer{"a": {Name: "a", Port: 1}},
+		Backups: []KeyByServer{{Name: "b", Port: 2}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	expectedChanged := []string{"backups", "servers"}
+	if !reflect.DeepEqual(changed, expectedChanged) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expectedChanged, changed)
+	}
+}
@@ -0,0 +1,85 @@
+package mapstructure
+
+import "reflect"
+
+// BeforeDecoder is implemented by types that want to rewrite their raw
+// input before it is decoded into them. BeforeDecode is called before the
+// DecodeHook, and the value it returns is decoded instead of the input.
+type BeforeDecoder interface {
+	BeforeDecode(input interface{}) (interface{}, error)
+}
+
+// AfterDecoder is implemented by types that want to normalize themselves
+// or compute derived fields once they have been decoded.
+type AfterDecoder interface {
+	AfterDecode() error
+}
+
+// Validator is implemented by types that check themselves once they have
+// been decoded. Validate is called after AfterDecode.
+type Validator interface {
+	Validate() error
+}
+
+// lifecycleTarget returns the value whose methods are called for outVal,
+// preferring its address so that methods with pointer receivers are found.
+// Pointers and interfaces are skipped since the value they point to gets
+// decoded, and gets its methods called, on its own.
+func lifecycleTarget(outVal reflect.Value) (interface{}, bool) {
+	switch outVal.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Invalid:
+		return nil, false
+	}
+
+	if outVal.CanAddr() {
+		return outVal.Addr().Interface(), true
+	}
+
+	if outVal.CanInterface() {
+		return outVal.Interface(), true
+	}
+
+	return nil, false
+}
+
+// beforeDecode calls BeforeDecode on outVal if it implements BeforeDecoder.
+func (d *Decoder) beforeDecode(name string, input interface{}, outVal reflect.Value) (interface{}, error) {
+	target, ok := lifecycleTarget(outVal)
+	if !ok {
+		return input, nil
+	}
+
+	if b, ok := target.(BeforeDecoder); ok {
+		result, err := b.BeforeDecode(input)
+		if err != nil {
+			return nil, newDecodeError(name, err)
+		}
+		return result, nil
+	}
+
+	return input, nil
+}
+
+// afterDecode calls AfterDecode and Validate on outVal if it implements
+// AfterDecoder or Validator. Since it runs once outVal has been decoded,
+// nested values are handled before the values containing them.
+func (d *Decoder) afterDecode(name string, outVal reflect.Value) error {
+	target, ok := lifecycleTarget(outVal)
+	if !ok {
+		return nil
+	}
+
+	if a, ok := target.(AfterDecoder); ok {
+		if err := a.AfterDecode(); err != nil {
+			return newDecodeError(name, err)
+		}
+	}
+
+	if v, ok := target.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return newDecodeError(name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type lifecyclePort struct {
+	Number int
+}
+
+func (p *lifecyclePort) BeforeDecode(input interface{}) (interface{}, error) {
+	// Accept a bare number as shorthand for {"number": n}.
+	if n, ok := input.(int); ok {
+		return map[string]interface{}{"number": n}, nil
+	}
+	return input, nil
+}
+
+func (p lifecyclePort) Validate() error {
+	if p.Number <= 0 || p.Number > 65535 {
+		return errors.New("port out of range")
+	}
+	return nil
+}
+
+type lifecycleServer struct {
+	Host string
+	Port lifecyclePort
+	Addr string
+}
+
+func (s *lifecycleServer) AfterDecode() error {
+	s.Host = strings.ToLower(s.Host)
+	s.Addr = s.Host + ":" + strconv.Itoa(s.Port.Number)
+	return nil
+}
+
+func (s *lifecycleServer) Validate() error {
+	if s.Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+func TestDecoder_CallLifecycleMethods(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Servers []lifecycleServer
+		Named   map[string]lifecycleServer
+	}
+
+	input := map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{"host": "A.example", "port": 80},
+			map[string]interface{}{"host": "b.example", "port": map[string]interface{}{"number": 70000}},
+			map[string]interface{}{"port": 443},
+		},
+		"named": map[string]interface{}{
+			"primary": map[string]interface{}{"host": "", "port": 8080},
+			"backup":  map[string]interface{}{"host": "d.example", "port": 0},
+		},
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, CallLifecycleMethods: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, expected := range []string{
+		"'Servers[1].Port' port out of range",
+		"'Servers[2]' host is required",
+		"'Named[backup].Port' port out of range",
+		"'Named[primary]' host is required",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in error:\n%s", expected, err)
+		}
+	}
+
+	if result.Servers[0].Host != "a.example" || result.Servers[0].Addr != "a.example:80" {
+		t.Fatalf("bad: %#v", result.Servers)
+	}
+}
+
+func TestDecoder_CallLifecycleMethodsDisabled(t *testing.T) {
+	t.Parallel()
+
+	var result lifecycleServer
+	if err := Decode(map[string]interface{}{"port": map[string]interface{}{"number": 0}}, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if result.Addr != "" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+type lifecycleBeforeError struct{}
+
+func (lifecycleBeforeError) BeforeDecode(interface{}) (interface{}, error) {
+	return nil, errors.New("rejected")
+}
+
+func TestDecoder_BeforeDecodeError(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Value lifecycleBeforeError
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, CallLifecycleMethods: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(map[string]interface{}{"value": "x"})
+	var derr *DecodeError
+	if !errors.As(err, &derr) || derr.Name() != "Value" {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+}
+
+type lifecycleTotals map[string]int
+
+func (t lifecycleTotals) AfterDecode() error {
+	total := 0
+	for key, n := range t {
+		if key != "total" {
+			total += n
+		}
+	}
+	t["total"] = total
+	return nil
+}
+
+func (t lifecycleTotals) Validate() error {
+	if _, ok := t["a"]; !ok {
+		return errors.New("a is required")
+	}
+	return nil
+}
+
+func TestDecoder_CallLifecycleMethodsMergedSlice(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Totals lifecycleTotals
+	}
+
+	input := map[string]interface{}{
+		"totals": []interface{}{
+			map[string]interface{}{"b": 2},
+			map[string]interface{}{"a": 1, "c": 3},
+		},
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, CallLifecycleMethods: true, WeaklyTypedInput: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := lifecycleTotals{"a": 1, "b": 2, "c": 3, "total": 6}
+	if !reflect.DeepEqual(result.Totals, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result.Totals)
+	}
+}
@@ -34,6 +34,34 @@
 //	    Username string `mapstructure:"user"`
 //	}
 //
+// # Field Aliases
+//
+// When a key is renamed, the old name can be kept working for a while by
+// listing it in the "alias" tag option. Multiple aliases are separated by
+// "|":
+//
+//	type Config struct {
+//	    Timeout int `mapstructure:"timeout,alias=request_timeout|rt"`
+//	}
+//
+// The primary name is tried first, followed by each of the aliases. It is
+// an error for more than one of them to be present in the input. When an
+// alias is used, it is recorded in Metadata and the DeprecationHandler in
+// DecoderConfig is called, if set.
+//
+// # Nested Keys
+//
+// With KeyDelimiter set in DecoderConfig, the key in a tag can be a path
+// into nested maps, which saves wrapper structs for deeply nested input:
+//
+//	type Config struct {
+//	    Host string `mapstructure:"database.connection.host"`
+//	}
+//
+// With a KeyDelimiter of ".", Host is decoded from
+// {"database": {"connection": {"host": "localhost"}}}, and decoding a
+// Config into a map produces the same nested maps.
+//
 // # Embedded Structs and Squashing
 //
 // Embedded structs are treated as if they're another field with that name.
@@ -86,12 +114,34 @@
 // DecoderConfig has a field that changes the behavior of mapstructure
 // to always squash embedded structs.
 //
+// The "prefix" option puts a prefix in front of the keys of a squashed
+// struct, so that the same struct can be squashed more than once:
+//
+//	type Server struct {
+//	    TLS      TLSConfig `mapstructure:",squash,prefix=tls_"`
+//	    AdminTLS TLSConfig `mapstructure:",squash,prefix=admin_tls_"`
+//	}
+//
+// Here the Cert field of TLSConfig is decoded from "tls_cert" and
+// "admin_tls_cert" respectively. Prefixes of nested squashed structs add
+// up.
+//
+// A squashed struct may have a field with the same name as a field of the
+// outer struct or of another squashed struct. By default decoding sets all
+// of them, and decoding into a map keeps the field that comes last. Set
+// KeyCollisions in DecoderConfig to report these collisions as errors or to
+// let the shallowest field win, like Go does for embedded fields.
+//
 // # Remainder Values
 //
 // If there are any unmapped keys in the source value, mapstructure by
 // default will silently ignore them. You can error by setting ErrorUnused
 // in DecoderConfig. If you're using Metadata you can also maintain a slice
-// of the unused keys.
+// of the unused keys. Unused keys close to the name of a field, such as
+// "hots" for "host", come with suggestions in both the UnusedKeysError and
+// Metadata.Suggestions.
+// Keys meant for other tools, such as "$schema", can be excluded from the
+// unused keys with patterns in the IgnoreUnusedKeys field of DecoderConfig.
 //
 // You can also use the ",remain" suffix on your tag to collect all unused
 // values in a map. The field with this tag MUST be a map type and should
@@ -111,6 +161,48 @@
 //	    "address": "123 Maple St.",
 //	}
 //
+// # Keyed Slices
+//
+// The "keyby" option converts between a slice of elements and a map of the
+// same elements, keyed by one of their keys. This way a list in the input
+// can be decoded into a map:
+//
+//	type Config struct {
+//	    Servers map[string]Server `mapstructure:"servers,keyby=name"`
+//	}
+//
+// Here {"servers": [{"name": "a"}, {"name": "b"}]} decodes into a map with
+// the keys "a" and "b", and it is an error for two elements to have the
+// same name. The other way around, a map in the input decodes into a slice
+// field sorted by key, with the key set as the name of each element.
+// Decoding the struct into a map converts the field the same way, so that
+// it gets the shape it would be decoded from.
+//
+// # Tuples
+//
+// A struct can be decoded from a slice or array, assigning its elements to
+// the fields by position. A blank field with the "tuple" option marks a
+// struct as a tuple everywhere it is decoded:
+//
+//	type Endpoint struct {
+//	    _      struct{} `mapstructure:",tuple"`
+//	    Host   string
+//	    Port   int
+//	    Secure bool `mapstructure:",omitempty"`
+//	}
+//
+// Here ["10.0.0.1", 8080, true] decodes into an Endpoint. Alternatively, the
+// "tuple" option on a field of struct type, or of a slice of structs, decodes
+// just that field from a tuple, or from a slice of tuples.
+//
+// Fields take their position in the order they are declared in, or the one
+// set with the "index" option, as in `mapstructure:"port,index=2"`, and the
+// fields following it continue from there. It is an error for the input to
+// have more elements than the struct has positions, or to stop before the
+// last field that isn't "omitempty", so trailing "omitempty" fields are
+// optional. Decoding a tuple struct into a map or slice turns it back into a
+// slice, leaving out the trailing optional fields that are omitted.
+//
 // # Omit Empty Values
 //
 // When decoding from a struct to any other value, you may use the
@@ -173,6 +265,22 @@
 //	    Public: "I made it through!"
 //	}
 //
+// # Generated Code
+//
+// The mapstructure-gen command writes methods that decode maps into struct
+// types, and back, without walking their fields through reflection:
+//
+//	//go:generate go run github.com/CoverWhale/mapstructure/v2/cmd/mapstructure-gen -type Config
+//
+// Besides the DecodeMapstructure and EncodeMapstructure methods that can be
+// called directly, the Decoder picks up the generated code by itself when
+// decoding a map[string]interface{} into one of these types or the other
+// way around, as long as its configuration doesn't use features the
+// generated code leaves to reflection, such as hooks or Metadata. Only
+// fields of basic types skip reflection entirely; the others are still
+// decoded by the Decoder. The result is the same either way. See
+// GeneratedDecoder.
+//
 // # Other Configuration
 //
 // mapstructure is highly configurable. See the DecoderConfig struct
@@ -186,6 +294,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/CoverWhale/mapstructure/v2/internal/errors"
 )
@@ -232,11 +341,54 @@ type DecoderConfig struct {
 	// If an error is returned, the entire decode will fail with that error.
 	DecodeHook DecodeHookFunc
 
+	// PathHooks are decode hooks that only run for the values whose names
+	// match their pattern, before DecodeHook. Names are the ones decoding
+	// errors use, such as "servers[0].tls", and patterns are written like
+	// those of IgnoreUnusedKeys, for example "servers[*].tls" or
+	// "plugins.*.options". When several patterns match a value, their
+	// hooks run in the order of the patterns, each seeing the result of the
+	// previous one.
+	PathHooks map[string]DecodeHookFunc
+
+	// HookRegistry, if set, holds decode hooks registered for specific
+	// source and target types. Only the hooks matching the types of a
+	// value are run, before the DecodeHook.
+	HookRegistry *HookRegistry
+
+	// EncodeHook, if set, will be called for every field value when a
+	// struct is decoded into a map, before the value is put into the map.
+	// It is the counterpart of DecodeHook and can turn values such as
+	// time.Duration back into the strings they were decoded from. The
+	// elements of slices, arrays and maps the hook leaves unchanged are
+	// passed to it too. Embedded structs that are squashed aren't passed
+	// to the hook, only their fields are.
+	//
+	// If an error is returned, the entire decode will fail with that error.
+	EncodeHook EncodeHookFunc
+
+	// Redaction, if set, redacts the values of fields tagged with the
+	// "sensitive" option when a struct is decoded into a map. Structs
+	// decoded into other structs keep their values. See Redaction for
+	// details.
+	Redaction *Redaction
+
 	// If ErrorUnused is true, then it is an error for there to exist
 	// keys in the original map that were unused in the decoding process
-	// (extra keys).
+	// (extra keys). The error is an UnusedKeysError, which suggests the
+	// closest matching field names for keys that look like misspellings.
 	ErrorUnused bool
 
+	// IgnoreUnusedKeys lists patterns of keys that are never reported as
+	// unused, such as keys meant for other tools. Patterns are matched
+	// against the full path of a key, written like the paths of Get, and
+	// each segment can be a glob as understood by path.Match. A "**"
+	// segment matches any number of segments, so "$schema" matches the
+	// key at the top level only, "**.x-*" matches keys starting with "x-"
+	// at any depth and "servers[*]._comment" matches the "_comment" key of
+	// every element of servers. Ignored keys are listed in
+	// Metadata.Ignored instead of Metadata.Unused.
+	IgnoreUnusedKeys []string
+
 	// If ErrorUnset is true, then it is an error for there to exist
 	// fields in the result that were not set in the decoding process
 	// (extra fields). This only applies to decoding to a struct. This
@@ -297,6 +449,24 @@ type DecoderConfig struct {
 	// be squashed. This defaults to "squash".
 	SquashTagOption string
 
+	// KeyDelimiter, if set, lets the key in a tag be a path of keys
+	// separated by the delimiter, such as "database.connection.host" with
+	// a KeyDelimiter of ".". The field is then decoded from the value
+	// nested at that path in the input maps, and decoding the struct into
+	// a map rebuilds the nested maps. Keys of the nested maps that no
+	// field reads are reported as unused, or put into the "remain" field,
+	// by their path. By default the key in a tag is always a single key.
+	KeyDelimiter string
+
+	// KeyCollisions decides what happens when more than one field of a
+	// struct resolves to the same key once squashed structs are flattened
+	// into it. This applies both when decoding into a struct and when
+	// decoding a struct into a map. When decoding into a struct, fields
+	// collide if MatchName matches their keys, such as "name" and "Name"
+	// by default; in a map, keys only collide if they are exactly the
+	// same. Defaults to KeyCollisionIgnore.
+	KeyCollisions KeyCollisionPolicy
+
 	// IgnoreUntaggedFields ignores all struct fields without explicit
 	// TagName, comparable to `mapstructure:"-"` as default behaviour.
 	IgnoreUntaggedFields bool
@@ -306,9 +476,73 @@ type DecoderConfig struct {
 	// to implement case-sensitive tag values, support snake casing, etc.
 	MatchName func(mapKey, fieldName string) bool
 
-	// DecodeNil, if set to true, will cause the DecodeHook (if present) to run
-	// even if the input is nil. This can be used to provide default values.
+	// DecodeNil, if set to true, will cause the DecodeHook and PathHooks (if
+	// present) to run even if the input is nil. This can be used to provide
+	// default values.
 	DecodeNil bool
+
+	// DeprecationHandler, if set, is called whenever a field is decoded
+	// from one of its aliases (see the "alias" tag option) instead of its
+	// primary name. name is the full path of the field using its primary
+	// name and alias is the full path of the key that was actually used.
+	// This can be used to log warnings about deprecated keys.
+	DeprecationHandler func(name, alias string)
+
+	// Validate, if set to true, will check the decoded result against the
+	// rules in the validate tags of its fields once decoding succeeded.
+	// Rules are separated by commas, for example `validate:"min=1,max=10"`.
+	// The following rules are available:
+	//
+	//   - min=N, max=N: numbers must be at least/most N, strings, slices
+	//     and maps must have at least/most N elements
+	//   - len=N: strings, slices and maps must have exactly N elements
+	//   - oneof=a b c: the value must be one of the space separated options
+	//   - pattern=RE: strings must match the regular expression. Since it
+	//     may contain commas, this must be the last rule of the tag.
+	//   - nonempty: the value must not be empty or zero
+	//   - url: strings must be absolute URLs
+	//
+	// Failures are reported as DecodeErrors wrapping a ValidationError,
+	// named after the path of the field in the input, such as
+	// "servers[2].port".
+	Validate bool
+
+	// ValidateTagName is the tag holding the validation rules. This
+	// defaults to "validate".
+	ValidateTagName string
+
+	// ValidationRules adds custom validation rules, or replaces the
+	// built-in ones, by name.
+	ValidationRules map[string]ValidationRule
+
+	// CallLifecycleMethods, if set to true, will call the methods of the
+	// BeforeDecoder, AfterDecoder and Validator interfaces on the values
+	// being decoded into. Errors returned by them are reported like any
+	// other decoding error, with the path of the value.
+	CallLifecycleMethods bool
+
+	// Atomic, if set to true, decodes into a copy of the result and only
+	// stores it in Result if decoding, including validation, succeeded.
+	// On error Result is left exactly as it was, which also holds when
+	// ZeroFields is false and the input is merged into existing values.
+	// The copy is a deep copy of the current value of Result, so this
+	// costs an extra allocation for every pointer, map and slice in it.
+	Atomic bool
+
+	// MaxErrors limits how many errors are collected before decoding
+	// stops. CollectAllErrors, the default, reports every error while
+	// FailFast stops at the first one, which is cheaper when only success
+	// matters. Any other positive value stops after that many errors.
+	//
+	// The limit applies to every kind of value. Errors are reported in a
+	// stable order: struct fields in the order they are declared, slice
+	// and array elements by index and map entries sorted by key.
+	MaxErrors int
+
+	// IgnoreGenerated, if set to true, decodes types with methods written
+	// by mapstructure-gen through reflection like any other type. See
+	// GeneratedDecoder.
+	IgnoreGenerated bool
 }
 
 // A Decoder takes a raw interface value and turns it into structured
@@ -320,6 +554,28 @@ type DecoderConfig struct {
 type Decoder struct {
 	config           *DecoderConfig
 	cachedDecodeHook func(from reflect.Value, to reflect.Value) (interface{}, error)
+	structPlans      *sync.Map
+
+	// errState counts the errors of the current call to Decode when
+	// MaxErrors is set. It is only set on the copy of the Decoder that
+	// Decode makes for the call.
+	errState *errorState
+
+	// useGenerated is set if the configuration allows using the methods
+	// written by mapstructure-gen.
+	useGenerated bool
+
+	// ignoreUnused holds the compiled IgnoreUnusedKeys patterns.
+	ignoreUnused []pathPattern
+
+	// pathHooks holds the compiled PathHooks.
+	pathHooks []pathHook
+
+	// merging is set on the copy of the Decoder that decodeMapFromSlice
+	// decodes each element with. The elements only make up part of the
+	// map, so its lifecycle methods are left to the call decoding the
+	// whole slice.
+	merging bool
 }
 
 // Metadata contains information about decoding a structure that
@@ -336,6 +592,18 @@ type Metadata struct {
 	// but weren't set in the decoding process since there was no matching value
 	// in the input
 	Unset []string
+
+	// Aliases maps the keys of fields that were decoded from one of their
+	// aliases to the alias that was actually found in the raw value.
+	Aliases map[string]string
+
+	// Ignored is a slice of keys that weren't decoded like the Unused ones,
+	// but matched one of the IgnoreUnusedKeys patterns.
+	Ignored []string
+
+	// Suggestions maps the Unused keys that look like misspellings of a
+	// field name to the closest matching field names of their struct.
+	Suggestions map[string][]string
 }
 
 // Decode takes an input structure and uses reflection to translate it to
@@ -431,8 +699,32 @@ func NewDecoder(config *DecoderConfig) (*Decoder, error) {
 		if config.Metadata.Unset == nil {
 			config.Metadata.Unset = make([]string, 0)
 		}
+
+		if config.Metadata.Aliases == nil {
+			config.Metadata.Aliases = make(map[string]string)
+		}
+
+		if config.Metadata.Ignored == nil {
+			config.Metadata.Ignored = make([]string, 0)
+		}
+
+		if config.Metadata.Suggestions == nil {
+			config.Metadata.Suggestions = make(map[string][]string)
+		}
+	}
+
+	ignoreUnused, err := compileIgnoreUnusedKeys(config.IgnoreUnusedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	pathHooks, err := compilePathHooks(config.PathHooks)
+	if err != nil {
+		return nil, err
 	}
 
+	useGenerated := canUseGenerated(config)
+
 	if config.TagName == "" {
 		config.TagName = "mapstructure"
 	}
@@ -445,10 +737,23 @@ func NewDecoder(config *DecoderConfig) (*Decoder, error) {
 		config.MatchName = strings.EqualFold
 	}
 
+	if config.ValidateTagName == "" {
+		config.ValidateTagName = "validate"
+	}
+
 	result := &Decoder{
-		config: config,
+		config:       config,
+		structPlans:  new(sync.Map),
+		useGenerated: useGenerated,
+		ignoreUnused: ignoreUnused,
+		pathHooks:    pathHooks,
 	}
-	if config.DecodeHook != nil {
+	switch {
+	case config.HookRegistry != nil && config.DecodeHook != nil:
+		result.cachedDecodeHook = cachedDecodeHook(ComposeDecodeHookFunc(config.HookRegistry.DecodeHook(), config.DecodeHook))
+	case config.HookRegistry != nil:
+		result.cachedDecodeHook = config.HookRegistry.DecodeHook()
+	case config.DecodeHook != nil:
 		result.cachedDecodeHook = cachedDecodeHook(config.DecodeHook)
 	}
 
@@ -458,7 +763,23 @@ func NewDecoder(config *DecoderConfig) (*Decoder, error) {
 // Decode decodes the given raw interface to the target pointer specified
 // by the configuration.
 func (d *Decoder) Decode(input interface{}) error {
-	err := d.decode("", input, reflect.ValueOf(d.config.Result).Elem())
+	if d.config.MaxErrors > 0 {
+		// Errors are counted on a copy, leaving d untouched for other
+		// calls.
+		call := *d
+		call.errState = newErrorState()
+		d = &call
+	}
+
+	var err error
+	if d.config.Atomic {
+		err = d.decodeAtomic(input)
+	} else {
+		err = d.decode("", input, reflect.ValueOf(d.config.Result).Elem())
+		if err == nil && d.config.Validate {
+			err = d.validate("", reflect.ValueOf(d.config.Result).Elem())
+		}
+	}
 
 	// Retain some of the original behavior when multiple errors ocurr
 	var joinedErr interface{ Unwrap() []error }
@@ -483,8 +804,16 @@ func (d *Decoder) decode(name string, input interface{}, outVal reflect.Value) e
 	var (
 		inputVal   = reflect.ValueOf(input)
 		outputKind = getKind(outVal)
-		decodeNil  = d.config.DecodeNil && d.cachedDecodeHook != nil
+		decodeNil  = d.config.DecodeNil && (d.cachedDecodeHook != nil || len(d.pathHooks) > 0)
+		merging    = d.merging
 	)
+	if merging {
+		// Only the map itself is being merged into, not the values
+		// decoded for it.
+		call := *d
+		call.merging = false
+		d = &call
+	}
 	if isNil(input) {
 		// Typed nils won't match the "input == nil" below, so reset input.
 		input = nil
@@ -526,6 +855,32 @@ func (d *Decoder) decode(name string, input interface{}, outVal reflect.Value) e
 		}
 	}
 
+	if d.config.CallLifecycleMethods && input != nil && !merging {
+		// Let the target rewrite its raw input first.
+		var err error
+		input, err = d.beforeDecode(name, input, outVal)
+		if err != nil {
+			return err
+		}
+		inputVal = reflect.ValueOf(input)
+		if !inputVal.IsValid() {
+			return nil
+		}
+	}
+
+	if len(d.pathHooks) > 0 {
+		// Hooks for this value in particular go first.
+		var err error
+		input, err = d.runPathHooks(name, inputVal, outVal)
+		if err != nil {
+			return newDecodeError(name, err)
+		}
+		if isNil(input) {
+			return nil
+		}
+		inputVal = reflect.ValueOf(input)
+	}
+
 	if d.cachedDecodeHook != nil {
 		// We have a DecodeHook, so let's pre-process the input.
 		var err error
@@ -572,6 +927,10 @@ func (d *Decoder) decode(name string, input interface{}, outVal reflect.Value) e
 		return newDecodeError(name, fmt.Errorf("unsupported type: %s", outputKind))
 	}
 
+	if err == nil && d.config.CallLifecycleMethods && !merging {
+		err = d.afterDecode(name, outVal)
+	}
+
 	// If we reached here, then we successfully decoded SOMETHING, so
 	// mark the key as used if we're tracking metainput.
 	if addMetaKey && d.config.Metadata != nil && name != "" {
@@ -979,16 +1338,29 @@ func (d *Decoder) decodeMapFromSlice(name string, dataVal reflect.Value, val ref
 		return nil
 	}
 
+	// Lifecycle methods are called once the elements are merged.
+	merge := d
+	if d.config.CallLifecycleMethods {
+		merge = new(Decoder)
+		*merge = *d
+		merge.merging = true
+	}
+
+	var errs []error
+
 	for i := 0; i < dataVal.Len(); i++ {
-		err := d.decode(
+		err := merge.decode(
 			name+"["+strconv.Itoa(i)+"]",
 			dataVal.Index(i).Interface(), val)
 		if err != nil {
-			return err
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 		}
 	}
 
-	return nil
+	return d.joinErrors(errs)
 }
 
 func (d *Decoder) decodeMapFromMap(name string, dataVal reflect.Value, val reflect.Value, valMap reflect.Value) error {
@@ -1013,13 +1385,19 @@ func (d *Decoder) decodeMapFromMap(name string, dataVal reflect.Value, val refle
 		return nil
 	}
 
-	for _, k := range dataVal.MapKeys() {
+	keys := dataVal.MapKeys()
+	sortMapKeys(keys)
+
+	for _, k := range keys {
 		fieldName := name + "[" + k.String() + "]"
 
 		// First decode the key into the proper type
 		currentKey := reflect.Indirect(reflect.New(valKeyType))
 		if err := d.decode(fieldName, k.Interface(), currentKey); err != nil {
-			errs = append(errs, err)
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 			continue
 		}
 
@@ -1027,7 +1405,10 @@ func (d *Decoder) decodeMapFromMap(name string, dataVal reflect.Value, val refle
 		v := dataVal.MapIndex(k).Interface()
 		currentVal := reflect.Indirect(reflect.New(valElemType))
 		if err := d.decode(fieldName, v, currentVal); err != nil {
-			errs = append(errs, err)
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 			continue
 		}
 
@@ -1037,11 +1418,25 @@ func (d *Decoder) decodeMapFromMap(name string, dataVal reflect.Value, val refle
 	// Set the built up map to the value
 	val.Set(valMap)
 
-	return errors.Join(errs...)
+	return d.joinErrors(errs)
 }
 
 func (d *Decoder) decodeMapFromStruct(name string, dataVal reflect.Value, val reflect.Value, valMap reflect.Value) error {
+	if gen := d.generatedEncoder(dataVal, valMap); gen != nil {
+		m, err := gen.EncodeMapstructureWith(GenSupport{d}, name)
+		for k, v := range m {
+			valMap.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(&v).Elem())
+		}
+		if val.CanAddr() {
+			val.Set(valMap)
+		}
+		return err
+	}
+
 	typ := dataVal.Type()
+	claims := d.newStructKeyClaims(name, typ)
+	var errs []error
+
 	for i := 0; i < typ.NumField(); i++ {
 		// Get the StructField first since this is a cheap operation. If the
 		// field is unexported, then ignore it.
@@ -1050,127 +1445,226 @@ func (d *Decoder) decodeMapFromStruct(name string, dataVal reflect.Value, val re
 			continue
 		}
 
-		// Next get the actual value of this field and verify it is assignable
-		// to the map value.
-		v := dataVal.Field(i)
-		if !v.Type().AssignableTo(valMap.Type().Elem()) {
-			return newDecodeError(
-				name+"."+f.Name,
-				fmt.Errorf("cannot assign type %q to map value field of type %q", v.Type(), valMap.Type().Elem()),
-			)
+		if err := d.decodeMapFromStructField(name, f, dataVal.Field(i), valMap, claims); err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 		}
+	}
 
-		tagValue := f.Tag.Get(d.config.TagName)
-		keyName := f.Name
+	if val.CanAddr() {
+		val.Set(valMap)
+	}
 
-		if tagValue == "" && d.config.IgnoreUntaggedFields {
-			continue
-		}
+	return d.joinErrors(errs)
+}
 
-		// If Squash is set in the config, we squash the field down.
-		squash := d.config.Squash && v.Kind() == reflect.Struct && f.Anonymous
+// decodeMapFromStructField puts a single field of a struct into valMap.
+// Keys shared with other fields are resolved with claims.
+func (d *Decoder) decodeMapFromStructField(name string, f reflect.StructField, v reflect.Value, valMap reflect.Value, claims *structKeyClaims) error {
+	// Verify the value of the field is assignable to the map value.
+	if !v.Type().AssignableTo(valMap.Type().Elem()) {
+		return newDecodeError(
+			name+"."+f.Name,
+			fmt.Errorf("cannot assign type %q to map value field of type %q", v.Type(), valMap.Type().Elem()),
+		)
+	}
 
-		v = dereferencePtrToStructIfNeeded(v, d.config.TagName)
+	tagValue := f.Tag.Get(d.config.TagName)
+	keyName := f.Name
 
-		// Determine the name of the key in the map
-		if index := strings.Index(tagValue, ","); index != -1 {
-			if tagValue[:index] == "-" {
-				continue
-			}
-			// If "omitempty" is specified in the tag, it ignores empty values.
-			if strings.Index(tagValue[index+1:], "omitempty") != -1 && isEmptyValue(v) {
-				continue
-			}
+	if tagValue == "" && d.config.IgnoreUntaggedFields {
+		return nil
+	}
 
-			// If "omitzero" is specified in the tag, it ignores zero values.
-			if strings.Index(tagValue[index+1:], "omitzero") != -1 && v.IsZero() {
-				continue
-			}
+	// If Squash is set in the config, we squash the field down.
+	squash := d.config.Squash && v.Kind() == reflect.Struct && f.Anonymous
 
-			// If "squash" is specified in the tag, we squash the field down.
-			squash = squash || strings.Contains(tagValue[index+1:], d.config.SquashTagOption)
-			if squash {
-				// When squashing, the embedded type can be a pointer to a struct.
-				if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
-					v = v.Elem()
-				}
+	v = dereferencePtrToStructIfNeeded(v, d.config.TagName)
+
+	// Determine the name of the key in the map
+	if index := strings.Index(tagValue, ","); index != -1 {
+		if tagValue[:index] == "-" {
+			return nil
+		}
+		// If "omitempty" is specified in the tag, it ignores empty values.
+		if strings.Index(tagValue[index+1:], "omitempty") != -1 && isEmptyValue(v) {
+			return nil
+		}
 
-				// The final type must be a struct
-				if v.Kind() != reflect.Struct {
+		// If "omitzero" is specified in the tag, it ignores zero values.
+		if strings.Index(tagValue[index+1:], "omitzero") != -1 && v.IsZero() {
+			return nil
+		}
+
+		// If "squash" is specified in the tag, we squash the field down.
+		squash = squash || strings.Contains(tagValue[index+1:], d.config.SquashTagOption)
+		if squash {
+			// When squashing, the embedded type can be a pointer to a struct.
+			if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+				v = v.Elem()
+			}
+
+			// The final type must be a struct
+			if v.Kind() != reflect.Struct {
+				return newDecodeError(
+					name+"."+f.Name,
+					fmt.Errorf("cannot squash non-struct type %q", v.Type()),
+				)
+			}
+		} else {
+			if strings.Index(tagValue[index+1:], "remain") != -1 {
+				if v.Kind() != reflect.Map {
 					return newDecodeError(
 						name+"."+f.Name,
-						fmt.Errorf("cannot squash non-struct type %q", v.Type()),
+						fmt.Errorf("error remain-tag field with invalid type: %q", v.Type()),
 					)
 				}
-			} else {
-				if strings.Index(tagValue[index+1:], "remain") != -1 {
-					if v.Kind() != reflect.Map {
-						return newDecodeError(
-							name+"."+f.Name,
-							fmt.Errorf("error remain-tag field with invalid type: %q", v.Type()),
-						)
-					}
 
-					ptr := v.MapRange()
-					for ptr.Next() {
-						valMap.SetMapIndex(ptr.Key(), ptr.Value())
-					}
-					continue
+				ptr := v.MapRange()
+				for ptr.Next() {
+					valMap.SetMapIndex(ptr.Key(), ptr.Value())
 				}
+				return nil
 			}
-			if keyNameTagValue := tagValue[:index]; keyNameTagValue != "" {
-				keyName = keyNameTagValue
+		}
+		if keyNameTagValue := tagValue[:index]; keyNameTagValue != "" {
+			keyName = keyNameTagValue
+		}
+	} else if len(tagValue) > 0 {
+		if tagValue == "-" {
+			return nil
+		}
+		keyName = tagValue
+	}
+
+	if !squash {
+		tuple, err := d.tupleFieldOutput(name+"."+keyName, hasTagOption(strings.Split(tagValue, ",")[1:], "tuple"), v)
+		if err != nil {
+			return err
+		}
+		v = tuple
+	}
+
+	if d.config.Redaction != nil && !squash {
+		if hasTagOption(strings.Split(tagValue, ",")[1:], "sensitive") {
+			if d.config.Redaction.Remove {
+				return nil
 			}
-		} else if len(tagValue) > 0 {
-			if tagValue == "-" {
-				continue
+			v = reflect.ValueOf(d.config.Redaction.placeholder())
+		} else if v.Kind() != reflect.Struct {
+			redacted, err := d.redactValue(name+"."+keyName, v)
+			if err != nil {
+				return err
 			}
-			keyName = tagValue
+			v = redacted
+		}
+	}
+
+	if keyBy := tagKeyBy(strings.Split(tagValue, ",")[1:]); keyBy != "" && !squash {
+		keyed, err := d.keyByOutput(name+"."+keyName, keyBy, v)
+		if err != nil {
+			return err
 		}
+		v = keyed
+	}
 
-		switch v.Kind() {
-		// this is an embedded struct, so handle it differently
-		case reflect.Struct:
-			x := reflect.New(v.Type())
-			x.Elem().Set(v)
+	if d.config.EncodeHook != nil && !squash {
+		encoded, err := d.encodeValue(name+"."+f.Name, v)
+		if err != nil {
+			return err
+		}
 
-			vType := valMap.Type()
-			vKeyType := vType.Key()
-			vElemType := vType.Elem()
-			mType := reflect.MapOf(vKeyType, vElemType)
-			vMap := reflect.MakeMap(mType)
+		if !encoded.IsValid() {
+			v = reflect.Zero(valMap.Type().Elem())
+		} else {
+			v = encoded
+		}
+	}
 
-			// Creating a pointer to a map so that other methods can completely
-			// overwrite the map if need be (looking at you decodeMapFromMap). The
-			// indirection allows the underlying map to be settable (CanSet() == true)
-			// where as reflect.MakeMap returns an unsettable map.
-			addrVal := reflect.New(vMap.Type())
-			reflect.Indirect(addrVal).Set(vMap)
+	// Redaction and encode hooks may have replaced the value.
+	if !v.Type().AssignableTo(valMap.Type().Elem()) {
+		return newDecodeError(
+			name+"."+f.Name,
+			fmt.Errorf("cannot assign type %q to map value field of type %q", v.Type(), valMap.Type().Elem()),
+		)
+	}
 
-			err := d.decode(keyName, x.Interface(), reflect.Indirect(addrVal))
-			if err != nil {
-				return err
+	keyPath := d.tagKeyPath(keyName)
+
+	switch v.Kind() {
+	// this is an embedded struct, so handle it differently
+	case reflect.Struct:
+		x := reflect.New(v.Type())
+		x.Elem().Set(v)
+
+		vType := valMap.Type()
+		vKeyType := vType.Key()
+		vElemType := vType.Elem()
+		mType := reflect.MapOf(vKeyType, vElemType)
+		vMap := reflect.MakeMap(mType)
+
+		// Creating a pointer to a map so that other methods can completely
+		// overwrite the map if need be (looking at you decodeMapFromMap). The
+		// indirection allows the underlying map to be settable (CanSet() == true)
+		// where as reflect.MakeMap returns an unsettable map.
+		addrVal := reflect.New(vMap.Type())
+		reflect.Indirect(addrVal).Set(vMap)
+
+		err := d.decode(keyName, x.Interface(), reflect.Indirect(addrVal))
+		if err != nil {
+			return err
+		}
+
+		// the underlying map may have been completely overwritten so pull
+		// it indirectly out of the enclosing value.
+		vMap = reflect.Indirect(addrVal)
+
+		if squash {
+			prefix := tagPrefix(strings.Split(tagValue, ",")[1:])
+			keys := vMap.MapKeys()
+			if claims == nil {
+				for _, k := range keys {
+					valMap.SetMapIndex(prefixMapKey(prefix, k), vMap.MapIndex(k))
+				}
+				return nil
 			}
 
-			// the underlying map may have been completely overwritten so pull
-			// it indirectly out of the enclosing value.
-			vMap = reflect.Indirect(addrVal)
+			// Keys are sorted so that collisions are reported in a
+			// stable order.
+			sortMapKeys(keys)
 
-			if squash {
-				for _, k := range vMap.MapKeys() {
-					valMap.SetMapIndex(k, vMap.MapIndex(k))
+			var errs []error
+			for _, k := range keys {
+				prefixed := prefixMapKey(prefix, k)
+				ok, err := claims.claim(fmt.Sprint(prefixed.Interface()), f.Name, true)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				if ok {
+					valMap.SetMapIndex(prefixed, vMap.MapIndex(k))
 				}
-			} else {
-				valMap.SetMapIndex(reflect.ValueOf(keyName), vMap)
 			}
+			return errors.Join(errs...)
+		}
 
-		default:
-			valMap.SetMapIndex(reflect.ValueOf(keyName), v)
+		if ok, err := claims.claim(keyName, f.Name, false); !ok {
+			return err
 		}
-	}
+		if keyPath != nil {
+			return d.setKeyPath(name, f, valMap, keyPath, vMap)
+		}
+		valMap.SetMapIndex(reflect.ValueOf(keyName), vMap)
 
-	if val.CanAddr() {
-		val.Set(valMap)
+	default:
+		if ok, err := claims.claim(keyName, f.Name, false); !ok {
+			return err
+		}
+		if keyPath != nil {
+			return d.setKeyPath(name, f, valMap, keyPath, v)
+		}
+		valMap.SetMapIndex(reflect.ValueOf(keyName), v)
 	}
 
 	return nil
@@ -1244,6 +1738,15 @@ func (d *Decoder) decodeSlice(name string, data interface{}, val reflect.Value)
 	valElemType := valType.Elem()
 	sliceType := reflect.SliceOf(valElemType)
 
+	// Tuple structs are encoded as slices.
+	if dataValKind == reflect.Struct && d.isTupleStruct(dataVal.Type()) {
+		tuple, err := d.tupleOutput(name, dataVal)
+		if err != nil {
+			return err
+		}
+		return d.decodeSlice(name, tuple, val)
+	}
+
 	// If we have a non array/slice type then we first attempt to convert.
 	if dataValKind != reflect.Array && dataValKind != reflect.Slice {
 		if d.config.WeaklyTypedInput {
@@ -1301,14 +1804,17 @@ func (d *Decoder) decodeSlice(name string, data interface{}, val reflect.Value)
 
 		fieldName := name + "[" + strconv.Itoa(i) + "]"
 		if err := d.decode(fieldName, currentData, currentField); err != nil {
-			errs = append(errs, err)
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 		}
 	}
 
 	// Finally, set the value to the slice we built up
 	val.Set(valSlice)
 
-	return errors.Join(errs...)
+	return d.joinErrors(errs)
 }
 
 func (d *Decoder) decodeArray(name string, data interface{}, val reflect.Value) error {
@@ -1362,14 +1868,17 @@ func (d *Decoder) decodeArray(name string, data interface{}, val reflect.Value)
 
 		fieldName := name + "[" + strconv.Itoa(i) + "]"
 		if err := d.decode(fieldName, currentData, currentField); err != nil {
-			errs = append(errs, err)
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
 		}
 	}
 
 	// Finally, set the value to the array we built up
 	val.Set(valArray)
 
-	return errors.Join(errs...)
+	return d.joinErrors(errs)
 }
 
 func (d *Decoder) decodeStruct(name string, data interface{}, val reflect.Value) error {
@@ -1388,27 +1897,28 @@ func (d *Decoder) decodeStruct(name string, data interface{}, val reflect.Value)
 		return d.decodeStructFromMap(name, dataVal, val)
 
 	case reflect.Struct:
-		// Not the most efficient way to do this but we can optimize later if
-		// we want to. To convert from struct to struct we go to map first
-		// as an intermediary.
+		// Sensitive fields are only redacted in maps, so they keep their
+		// values when the struct is decoded into another struct.
+		d = d.unredacted()
 
-		// Make a new map to hold our result
-		mapType := reflect.TypeOf((map[string]interface{})(nil))
-		mval := reflect.MakeMap(mapType)
+		// Fields are matched up directly when the types allow it. Otherwise
+		// we go to a map first as an intermediary.
+		if plan := d.structPlan(dataVal.Type(), val.Type()); plan != nil {
+			return d.decodeStructFromStruct(name, plan, dataVal, val)
+		}
 
-		// Creating a pointer to a map so that other methods can completely
-		// overwrite the map if need be (looking at you decodeMapFromMap). The
-		// indirection allows the underlying map to be settable (CanSet() == true)
-		// where as reflect.MakeMap returns an unsettable map.
-		addrVal := reflect.New(mval.Type())
+		return d.decodeStructViaMap(name, dataVal, val)
 
-		reflect.Indirect(addrVal).Set(mval)
-		if err := d.decodeMapFromStruct(name, dataVal, reflect.Indirect(addrVal), mval); err != nil {
-			return err
+	case reflect.Slice, reflect.Array:
+		// Tuple structs are decoded from their elements by position.
+		if d.isTupleStruct(val.Type()) {
+			input, err := d.tupleInput(name, dataVal, val.Type())
+			if err != nil {
+				return err
+			}
+			return d.decodeStructFromMap(name, reflect.ValueOf(input), val)
 		}
-
-		result := d.decodeStructFromMap(name, reflect.Indirect(addrVal), val)
-		return result
+		fallthrough
 
 	default:
 		return newDecodeError(name,
@@ -1416,6 +1926,28 @@ func (d *Decoder) decodeStruct(name string, data interface{}, val reflect.Value)
 	}
 }
 
+// decodeStructViaMap converts a struct to another struct by decoding it into
+// a map first. This is used for the struct types decodeStructFromStruct
+// can't handle.
+func (d *Decoder) decodeStructViaMap(name string, dataVal, val reflect.Value) error {
+	// Make a new map to hold our result
+	mapType := reflect.TypeOf((map[string]interface{})(nil))
+	mval := reflect.MakeMap(mapType)
+
+	// Creating a pointer to a map so that other methods can completely
+	// overwrite the map if need be (looking at you decodeMapFromMap). The
+	// indirection allows the underlying map to be settable (CanSet() == true)
+	// where as reflect.MakeMap returns an unsettable map.
+	addrVal := reflect.New(mval.Type())
+
+	reflect.Indirect(addrVal).Set(mval)
+	if err := d.decodeMapFromStruct(name, dataVal, reflect.Indirect(addrVal), mval); err != nil {
+		return err
+	}
+
+	return d.decodeStructFromMap(name, reflect.Indirect(addrVal), val)
+}
+
 func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) error {
 	dataValType := dataVal.Type()
 	if kind := dataValType.Key().Kind(); kind != reflect.String && kind != reflect.Interface {
@@ -1423,6 +1955,10 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 			fmt.Errorf("needs a map with string keys, has %q keys", kind))
 	}
 
+	if gen := d.generatedDecoder(dataVal, val); gen != nil {
+		return gen.DecodeMapstructureWith(GenSupport{d}, name, dataVal.Interface().(map[string]interface{}))
+	}
+
 	dataValKeys := make(map[reflect.Value]struct{})
 	dataValKeysUnused := make(map[interface{}]struct{})
 	for _, dataValKey := range dataVal.MapKeys() {
@@ -1440,11 +1976,18 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 	structs := make([]reflect.Value, 1, 5)
 	structs[0] = val
 
+	// The paths of the structs from the outer one, using Go field names,
+	// and the prefixes of their keys.
+	structPaths := make([]string, 1, 5)
+	structPrefixes := make([]string, 1, 5)
+
 	// Compile the list of all the fields that we're going to be decoding
 	// from all the structs.
 	type field struct {
-		field reflect.StructField
-		val   reflect.Value
+		field  reflect.StructField
+		val    reflect.Value
+		path   string
+		prefix string
 	}
 
 	// remainField is set to a valid field set with the "remain" tag if
@@ -1455,12 +1998,23 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 	for len(structs) > 0 {
 		structVal := structs[0]
 		structs = structs[1:]
+		structPath := structPaths[0]
+		structPaths = structPaths[1:]
+		structPrefix := structPrefixes[0]
+		structPrefixes = structPrefixes[1:]
 
 		structType := structVal.Type()
 
 		for i := 0; i < structType.NumField(); i++ {
 			fieldType := structType.Field(i)
 			fieldVal := structVal.Field(i)
+			if d.isTupleMarker(fieldType) {
+				continue
+			}
+			fieldPath := fieldType.Name
+			if structPath != "" {
+				fieldPath = structPath + "." + fieldType.Name
+			}
 			if fieldVal.Kind() == reflect.Ptr && fieldVal.Elem().Kind() == reflect.Struct {
 				// Handle embedded struct pointers as embedded structs.
 				fieldVal = fieldVal.Elem()
@@ -1485,12 +2039,17 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 			}
 
 			if squash {
+				prefix := structPrefix + tagPrefix(tagParts[1:])
 				switch fieldVal.Kind() {
 				case reflect.Struct:
 					structs = append(structs, fieldVal)
+					structPaths = append(structPaths, fieldPath)
+					structPrefixes = append(structPrefixes, prefix)
 				case reflect.Interface:
 					if !fieldVal.IsNil() {
 						structs = append(structs, fieldVal.Elem().Elem())
+						structPaths = append(structPaths, fieldPath)
+						structPrefixes = append(structPrefixes, prefix)
 					}
 				case reflect.Ptr:
 					if fieldVal.Type().Elem().Kind() == reflect.Struct {
@@ -1498,31 +2057,53 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 							fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
 						}
 						structs = append(structs, fieldVal.Elem())
+						structPaths = append(structPaths, fieldPath)
+						structPrefixes = append(structPrefixes, prefix)
 					} else {
-						errs = append(errs, newDecodeError(
+						var stop bool
+						errs, stop = d.addError(errs, newDecodeError(
 							name+"."+fieldType.Name,
 							fmt.Errorf("unsupported type for squashed pointer: %s", fieldVal.Type().Elem().Kind()),
 						))
+						if stop {
+							return d.joinErrors(errs)
+						}
 					}
 				default:
-					errs = append(errs, newDecodeError(
+					var stop bool
+					errs, stop = d.addError(errs, newDecodeError(
 						name+"."+fieldType.Name,
 						fmt.Errorf("unsupported type for squash: %s", fieldVal.Kind()),
 					))
+					if stop {
+						return d.joinErrors(errs)
+					}
 				}
 				continue
 			}
 
 			// Build our field
 			if remain {
-				remainField = &field{fieldType, fieldVal}
+				remainField = &field{fieldType, fieldVal, fieldPath, structPrefix}
 			} else {
 				// Normal struct field, store it away
-				fields = append(fields, field{fieldType, fieldVal})
+				fields = append(fields, field{fieldType, fieldVal, fieldPath, structPrefix})
 			}
 		}
 	}
 
+	var collisions map[string][]collidingField
+	if d.config.KeyCollisions != KeyCollisionIgnore {
+		collisions = d.keyCollisions(val.Type(), true)
+	}
+
+	// The keys the fields can be decoded from, to suggest in place of
+	// unused keys.
+	var fieldNames []string
+
+	// The parts of the input read by fields with a key path.
+	nested := make(nestedKeys)
+
 	// for fieldType, field := range fields {
 	for _, f := range fields {
 		field, fieldValue := f.field, f.val
@@ -1532,38 +2113,89 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 		if tagValue == "" && d.config.IgnoreUntaggedFields {
 			continue
 		}
-		tagValue = strings.SplitN(tagValue, ",", 2)[0]
-		if tagValue != "" {
-			fieldName = tagValue
+		tagParts := strings.Split(tagValue, ",")
+		if tagParts[0] != "" {
+			fieldName = tagParts[0]
+		}
+		fieldName = f.prefix + fieldName
+		aliases := tagAliases(tagParts[1:])
+		if f.prefix != "" {
+			for i, alias := range aliases {
+				aliases[i] = f.prefix + alias
+			}
+		}
+		if field.PkgPath == "" && fieldName != f.prefix+"-" {
+			fieldNames = append(fieldNames, fieldName)
+			fieldNames = append(fieldNames, aliases...)
 		}
 
-		rawMapKey := reflect.ValueOf(fieldName)
-		rawMapVal := dataVal.MapIndex(rawMapKey)
-		if !rawMapVal.IsValid() {
-			// Do a slower search by iterating over each key and
-			// doing case-insensitive search.
-			for dataValKey := range dataValKeys {
-				mK, ok := dataValKey.Interface().(string)
-				if !ok {
-					// Not a string key
-					continue
-				}
+		group, collides := collisions[fieldName]
+		if collides && d.config.KeyCollisions == KeyCollisionShallowest && shallowest(group) != f.path {
+			// The field is shadowed by a shallower one.
+			continue
+		}
 
-				if d.config.MatchName(mK, fieldName) {
-					rawMapKey = dataValKey
-					rawMapVal = dataVal.MapIndex(dataValKey)
-					break
-				}
+		var rawMapKey, rawMapVal reflect.Value
+		var nestedPath []string
+		keyPath := d.tagKeyPath(fieldName)
+		if keyPath != nil {
+			nestedPath, rawMapVal = d.lookupKeyPath(dataVal, dataValKeys, keyPath)
+			if rawMapVal.IsValid() {
+				rawMapKey = reflect.ValueOf(nestedPath[0])
+			}
+		} else {
+			rawMapKey, rawMapVal = d.lookupMapKey(dataVal, dataValKeys, fieldName)
+		}
+		if collides && d.config.KeyCollisions == KeyCollisionError && rawMapVal.IsValid() && group[0].path == f.path {
+			var stop bool
+			if errs, stop = d.addError(errs, collisionError(name, fieldName, group)); stop {
+				return d.joinErrors(errs)
 			}
+		}
 
-			if !rawMapVal.IsValid() {
-				// There was no matching key in the map for the value in
-				// the struct. Remember it for potential errors and metadata.
-				if !(d.config.AllowUnsetPointer && fieldValue.Kind() == reflect.Ptr) {
-					targetValKeysUnused[fieldName] = struct{}{}
-				}
+		// Try each of the aliases of the field as well. More than one of
+		// the names being present is a conflict since we can't tell which
+		// one was meant to win.
+		var aliasKey reflect.Value
+		var conflict bool
+		for _, alias := range aliases {
+			key, val := d.lookupMapKey(dataVal, dataValKeys, alias)
+			if !val.IsValid() || (rawMapVal.IsValid() && key.Interface() == rawMapKey.Interface()) {
 				continue
 			}
+
+			if rawMapVal.IsValid() {
+				keyName := fieldName
+				if name != "" {
+					keyName = name + "." + keyName
+				}
+				var stop bool
+				errs, stop = d.addError(errs, newDecodeError(keyName,
+					fmt.Errorf("conflicting keys %q and %q are both set", rawMapKey.Interface(), key.Interface())))
+				if stop {
+					return d.joinErrors(errs)
+				}
+				delete(dataValKeysUnused, rawMapKey.Interface())
+				delete(dataValKeysUnused, key.Interface())
+				conflict = true
+				break
+			}
+
+			rawMapKey, rawMapVal = key, val
+			aliasKey = key
+			nestedPath = nil
+		}
+		if conflict {
+			continue
+		}
+
+		if !rawMapVal.IsValid() {
+			// There was no matching key in the map for the value in
+			// the struct. Remember it for potential errors and metadata.
+			if !(d.config.AllowUnsetPointer && fieldValue.Kind() == reflect.Ptr) {
+				targetValKeysUnused[fieldName] = struct{}{}
+			}
+			continue
 		}
 
 		if !fieldValue.IsValid() {
@@ -1578,7 +2210,11 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 		}
 
 		// Delete the key we're using from the unused map so we stop tracking
-		delete(dataValKeysUnused, rawMapKey.Interface())
+		if nestedPath != nil {
+			nested.add(nestedPath)
+		} else {
+			delete(dataValKeysUnused, rawMapKey.Interface())
+		}
 
 		// If the name is empty string, then we're at the root, and we
 		// don't dot-join the fields.
@@ -1586,23 +2222,77 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 			fieldName = name + "." + fieldName
 		}
 
-		if err := d.decode(fieldName, rawMapVal.Interface(), fieldValue); err != nil {
-			errs = append(errs, err)
+		if aliasKey.IsValid() {
+			alias := fmt.Sprint(aliasKey.Interface())
+			if name != "" {
+				alias = name + "." + alias
+			}
+
+			if d.config.Metadata != nil {
+				d.config.Metadata.Aliases[fieldName] = alias
+			}
+
+			if d.config.DeprecationHandler != nil {
+				d.config.DeprecationHandler(fieldName, alias)
+			}
+		}
+
+		input := rawMapVal.Interface()
+		var err error
+		if keyBy := tagKeyBy(tagParts[1:]); keyBy != "" {
+			input, err = d.keyByInput(fieldName, keyBy, input, fieldValue)
+		}
+		if err == nil && hasTagOption(tagParts[1:], "tuple") {
+			input, err = d.tupleFieldInput(fieldName, input, fieldValue)
+		}
+		if err == nil {
+			err = d.decode(fieldName, input, fieldValue)
+		}
+		if err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				return d.joinErrors(errs)
+			}
 		}
 	}
 
+	// Keys holding maps that fields with a key path were read from are
+	// used, but the keys of those maps that weren't read are not, unless
+	// another field read the whole value. They are tracked by their key
+	// path, which is also their key in the "remain" field.
+	nestedUnused := make(map[string]interface{})
+	for key, used := range nested {
+		if _, ok := dataValKeysUnused[key]; !ok {
+			continue
+		}
+		delete(dataValKeysUnused, key)
+		d.unusedNestedKeys(key, dataVal.MapIndex(reflect.ValueOf(key)), used, nestedUnused)
+	}
+	for key := range nestedUnused {
+		dataValKeysUnused[key] = struct{}{}
+	}
+
 	// If we have a "remain"-tagged field and we have unused keys then
 	// we put the unused keys directly into the remain field.
 	if remainField != nil && len(dataValKeysUnused) > 0 {
 		// Build a map of only the unused values
 		remain := map[interface{}]interface{}{}
 		for key := range dataValKeysUnused {
+			if k, ok := key.(string); ok {
+				if v, ok := nestedUnused[k]; ok {
+					remain[key] = v
+					continue
+				}
+			}
 			remain[key] = dataVal.MapIndex(reflect.ValueOf(key)).Interface()
 		}
 
 		// Decode it as-if we were just decoding this map onto our map.
 		if err := d.decodeMap(name, remain, remainField.val); err != nil {
-			errs = append(errs, err)
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				return d.joinErrors(errs)
+			}
 		}
 
 		// Set the map to nil so we have none so that the next check will
@@ -1610,6 +2300,23 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 		dataValKeysUnused = nil
 	}
 
+	// Drop the keys matching IgnoreUnusedKeys from the unused ones.
+	var ignoredKeys []string
+	if len(d.ignoreUnused) > 0 && len(dataValKeysUnused) > 0 {
+		keys := make([]string, 0, len(dataValKeysUnused))
+		for rawKey := range dataValKeysUnused {
+			if key, ok := rawKey.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		_, ignoredKeys = d.ignoreUnusedKeys(name, keys)
+		for _, key := range ignoredKeys {
+			delete(dataValKeysUnused, key)
+		}
+	}
+
 	if d.config.ErrorUnused && len(dataValKeysUnused) > 0 {
 		keys := make([]string, 0, len(dataValKeysUnused))
 		for rawKey := range dataValKeysUnused {
@@ -1617,10 +2324,11 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 		}
 		sort.Strings(keys)
 
-		errs = append(errs, newDecodeError(
-			name,
-			fmt.Errorf("has invalid keys: %s", strings.Join(keys, ", ")),
-		))
+		var stop bool
+		errs, stop = d.addError(errs, unusedKeysError(name, keys, fieldNames))
+		if stop {
+			return d.joinErrors(errs)
+		}
 	}
 
 	if d.config.ErrorUnset && len(targetValKeysUnused) > 0 {
@@ -1630,13 +2338,13 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 		}
 		sort.Strings(keys)
 
-		errs = append(errs, newDecodeError(
+		errs, _ = d.addError(errs, newDecodeError(
 			name,
 			fmt.Errorf("has unset fields: %s", strings.Join(keys, ", ")),
 		))
 	}
 
-	if err := errors.Join(errs...); err != nil {
+	if err := d.joinErrors(errs); err != nil {
 		return err
 	}
 
@@ -1644,12 +2352,17 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 	if d.config.Metadata != nil {
 		for rawKey := range dataValKeysUnused {
 			key := rawKey.(string)
+			suggestions := suggestFieldName(key, fieldNames)
 			if name != "" {
 				key = name + "." + key
 			}
 
 			d.config.Metadata.Unused = append(d.config.Metadata.Unused, key)
+			if len(suggestions) > 0 {
+				d.config.Metadata.Suggestions[key] = suggestions
+			}
 		}
+		d.recordIgnoredKeys(name, ignoredKeys)
 		for rawKey := range targetValKeysUnused {
 			key := rawKey.(string)
 			if name != "" {
@@ -1663,6 +2376,66 @@ func (d *Decoder) decodeStructFromMap(name string, dataVal, val reflect.Value) e
 	return nil
 }
 
+// lookupMapKey finds the key in dataVal that matches fieldName. An exact
+// match is tried first, after which every key is compared using MatchName.
+// The returned values are invalid if there is no matching key.
+func (d *Decoder) lookupMapKey(dataVal reflect.Value, dataValKeys map[reflect.Value]struct{}, fieldName string) (reflect.Value, reflect.Value) {
+	rawMapKey := reflect.ValueOf(fieldName)
+	rawMapVal := dataVal.MapIndex(rawMapKey)
+	if rawMapVal.IsValid() {
+		return rawMapKey, rawMapVal
+	}
+
+	// Do a slower search by iterating over each key and
+	// doing case-insensitive search.
+	for dataValKey := range dataValKeys {
+		mK, ok := dataValKey.Interface().(string)
+		if !ok {
+			// Not a string key
+			continue
+		}
+
+		if d.config.MatchName(mK, fieldName) {
+			return dataValKey, dataVal.MapIndex(dataValKey)
+		}
+	}
+
+	return reflect.Value{}, reflect.Value{}
+}
+
+// tagAliases returns the aliases listed in the "alias" option of a tag.
+// Multiple aliases are separated by "|".
+func tagAliases(options []string) []string {
+	for _, option := range options {
+		if strings.HasPrefix(option, "alias=") {
+			return strings.Split(strings.TrimPrefix(option, "alias="), "|")
+		}
+	}
+
+	return nil
+}
+
+// prefixMapKey puts prefix in front of the map key k.
+func prefixMapKey(prefix string, k reflect.Value) reflect.Value {
+	if prefix == "" {
+		return k
+	}
+
+	return reflect.ValueOf(prefix + fmt.Sprint(k.Interface())).Convert(k.Type())
+}
+
+// tagPrefix returns the prefix set by the "prefix" option of a tag, which
+// is put in front of the keys of a squashed struct.
+func tagPrefix(options []string) string {
+	for _, option := range options {
+		if strings.HasPrefix(option, "prefix=") {
+			return strings.TrimPrefix(option, "prefix=")
+		}
+	}
+
+	return ""
+}
+
 func isEmptyValue(v reflect.Value) bool {
 	switch getKind(v) {
 	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
@@ -2,7 +2,9 @@ package mapstructure
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 )
 
 type Person struct {
@@ -309,3 +311,91 @@ func Benchmark_DecodeWithRemainingFields(b *testing.B) {
 		_ = Decode(&person, &result)
 	}
 }
+
+type PersonDTO struct {
+	Name   string `mapstructure:"name"`
+	Age    int    `mapstructure:"age"`
+	Emails []string
+	Extra  map[string]string
+}
+
+func Benchmark_DecodeStructToStruct(b *testing.B) {
+	input := PersonDTO{
+		Name:   "Mitchell",
+		Age:    91,
+		Emails: []string{"one", "two", "three"},
+		Extra: map[string]string{
+			"twitter": "mitchellh",
+		},
+	}
+
+	var result Person
+	decoder, _ := NewDecoder(&DecoderConfig{Result: &result})
+	for i := 0; i < b.N; i++ {
+		decoder.Decode(input)
+	}
+}
+
+func Benchmark_DecodeStructToStructViaMap(b *testing.B) {
+	input := PersonDTO{
+		Name:   "Mitchell",
+		Age:    91,
+		Emails: []string{"one", "two", "three"},
+		Extra: map[string]string{
+			"twitter": "mitchellh",
+		},
+	}
+
+	var result Person
+	decoder, _ := NewDecoder(&DecoderConfig{Result: &result})
+	dataVal := reflect.ValueOf(input)
+	val := reflect.ValueOf(&result).Elem()
+	for i := 0; i < b.N; i++ {
+		decoder.decodeStructViaMap("", dataVal, val)
+	}
+}
+
+type hookedConfig struct {
+	Timeout time.Duration
+	Name    string
+	Port    int
+	Tags    []string
+}
+
+var hookedInput = map[string]interface{}{
+	"timeout": "5s",
+	"name":    "server",
+	"port":    8080,
+	"tags":    []string{"a", "b", "c"},
+}
+
+func Benchmark_DecodeComposedHooks(b *testing.B) {
+	var result hookedConfig
+	decoder, _ := NewDecoder(&DecoderConfig{
+		Result: &result,
+		DecodeHook: ComposeDecodeHookFunc(
+			StringToTimeDurationHookFunc(),
+			StringToURLHookFunc(),
+			StringToIPHookFunc(),
+			StringToNetIPAddrHookFunc(),
+			StringToBasicTypeHookFunc(),
+		),
+	})
+	for i := 0; i < b.N; i++ {
+		decoder.Decode(hookedInput)
+	}
+}
+
+func Benchmark_DecodeHookRegistry(b *testing.B) {
+	registry := NewHookRegistry()
+	registry.RegisterStringHooks()
+
+	var result hookedConfig
+	decoder, _ := NewDecoder(&DecoderConfig{
+		Result:       &result,
+		HookRegistry: registry,
+	})
+	for i := 0; i < b.N; i++ {
+		decoder.Decode(hookedInput)
+	}
+}
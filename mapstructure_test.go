@@ -3525,3 +3525,146 @@ func uintPtr(v uint) *uint                    { return &v }
 func boolPtr(v bool) *bool                    { return &v }
 func floatPtr(v float64) *float64             { return &v }
 func interfacePtr(v interface{}) *interface{} { return &v }
+
+func TestDecoder_Alias(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout int    `mapstructure:"timeout,alias=request_timeout|rt"`
+		Name    string `mapstructure:"name,omitempty,alias=title"`
+	}
+
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected Target
+		aliases  map[string]string
+	}{
+		{
+			"primary",
+			map[string]interface{}{"timeout": 1, "name": "foo"},
+			Target{Timeout: 1, Name: "foo"},
+			map[string]string{},
+		},
+		{
+			"first alias",
+			map[string]interface{}{"request_timeout": 2, "title": "bar"},
+			Target{Timeout: 2, Name: "bar"},
+			map[string]string{"timeout": "request_timeout", "name": "title"},
+		},
+		{
+			"second alias",
+			map[string]interface{}{"RT": 3},
+			Target{Timeout: 3},
+			map[string]string{"timeout": "RT"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var actual Target
+			var md Metadata
+			deprecated := map[string]string{}
+			config := &DecoderConfig{
+				Result:      &actual,
+				Metadata:    &md,
+				ErrorUnused: true,
+				DeprecationHandler: func(name, alias string) {
+					deprecated[name] = alias
+				},
+			}
+
+			decoder, err := NewDecoder(config)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if err := decoder.Decode(tc.input); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("Decode() expected: %#v, got: %#v", tc.expected, actual)
+			}
+
+			if !reflect.DeepEqual(tc.aliases, md.Aliases) {
+				t.Fatalf("Metadata.Aliases expected: %#v, got: %#v", tc.aliases, md.Aliases)
+			}
+
+			if !reflect.DeepEqual(tc.aliases, deprecated) {
+				t.Fatalf("DeprecationHandler expected: %#v, got: %#v", tc.aliases, deprecated)
+			}
+		})
+	}
+}
+
+func TestDecoder_AliasNested(t *testing.T) {
+	t.Parallel()
+
+	type Inner struct {
+		Host string `mapstructure:"host,alias=hostname"`
+	}
+
+	type Target struct {
+		DB Inner `mapstructure:"db"`
+	}
+
+	input := map[string]interface{}{
+		"db": map[string]interface{}{"hostname": "localhost"},
+	}
+
+	var actual Target
+	var md Metadata
+	if err := DecodeMetadata(input, &actual, &md); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual.DB.Host != "localhost" {
+		t.Fatalf("bad: %#v", actual)
+	}
+
+	expected := map[string]string{"db.host": "db.hostname"}
+	if !reflect.DeepEqual(expected, md.Aliases) {
+		t.Fatalf("Metadata.Aliases expected: %#v, got: %#v", expected, md.Aliases)
+	}
+}
+
+func TestDecoder_AliasConflict(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Timeout int `mapstructure:"timeout,alias=request_timeout|rt"`
+	}
+
+	inputs := []map[string]interface{}{
+		{"timeout": 1, "rt": 2},
+		{"request_timeout": 1, "rt": 2},
+	}
+
+	for _, input := range inputs {
+		var actual Target
+		config := &DecoderConfig{
+			Result:      &actual,
+			ErrorUnused: true,
+		}
+
+		decoder, err := NewDecoder(config)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		err = decoder.Decode(input)
+		if err == nil {
+			t.Fatalf("expected error for %#v", input)
+		}
+
+		if !strings.Contains(err.Error(), "conflicting keys") {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if strings.Contains(err.Error(), "invalid keys") {
+			t.Fatalf("conflicting keys should not be reported as unused: %s", err)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7396) document onto
+// target, which must be a pointer, and returns the paths that changed in
+// sorted order.
+//
+// Keys that are absent from the patch are left untouched. A nil value
+// resets a field to its zero value or deletes a map key. Nested objects are
+// merged recursively into structs and maps, while any other value,
+// including slices, replaces the current one and is decoded like Decode
+// would. Field names, squashed structs, "remain" fields and aliases are
+// resolved using the tags of config, as is the "keyby" option, and its
+// decode hooks run on the values being replaced. The Result of config is
+// ignored.
+//
+// A value that fails to decode is left as it was, but the other paths of
+// the patch are still applied. Set Atomic in config to leave target
+// untouched if any error occurs.
+func ApplyMergePatch(target any, patch map[string]any, config *DecoderConfig) ([]string, error) {
+	var c DecoderConfig
+	if config != nil {
+		c = *config
+	}
+	c.Result = target
+
+	decoder, err := NewDecoder(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &patcher{decoder: decoder}
+	result := reflect.ValueOf(target).Elem()
+
+	if decoder.config.Atomic {
+		scratch := reflect.New(result.Type()).Elem()
+		scratch.Set(deepCopyValue(result, make(map[uintptr]reflect.Value)))
+		if err := p.patch("", scratch, patch); err != nil {
+			return nil, err
+		}
+		result.Set(scratch)
+	} else if err := p.patch("", result, patch); err != nil {
+		sort.Strings(p.changed)
+		return dedupeSorted(p.changed), err
+	}
+
+	sort.Strings(p.changed)
+	return dedupeSorted(p.changed), nil
+}
+
+// patcher holds the state of a single call to ApplyMergePatch.
+type patcher struct {
+	decoder *Decoder
+	changed []string
+}
+
+// patch merges the patch object into val.
+func (p *patcher) patch(name string, val reflect.Value, patch map[string]interface{}) error {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return p.patch(name, val.Elem(), patch)
+
+	case reflect.Interface:
+		current, _ := val.Interface().(map[string]interface{})
+		merged := p.patchObject(name, current, patch)
+		val.Set(reflect.ValueOf(merged))
+		return nil
+
+	case reflect.Struct:
+		return p.patchStruct(name, val, patch)
+
+	case reflect.Map:
+		return p.patchMap(name, val, patch, false)
+	}
+
+	// Anything else can't be merged into, so the patch replaces it.
+	return p.replace(name, val, patch)
+}
+
+func (p *patcher) patchStruct(name string, val reflect.Value, patch map[string]interface{}) error {
+	d := p.decoder
+	fields, remain := d.structKeyFields(val, true)
+
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	var unused []string
+	remainPatch := make(map[string]interface{})
+
+	for _, k := range keys {
+		field := d.findKeyField(fields, k)
+		if field == nil && d.hasKeyPathPrefix(fields, []string{k}) {
+			// The key leads to fields with a key path.
+			nestedUnused, err := p.patchKeyPath(name, fields, []string{k}, patch[k])
+			if err != nil {
+				var stop bool
+				if errs, stop = d.addError(errs, err); stop {
+					return d.joinErrors(errs)
+				}
+			}
+			for key, v := range nestedUnused {
+				if remain.IsValid() {
+					remainPatch[key] = v
+				} else {
+					unused = append(unused, key)
+				}
+			}
+			continue
+		}
+		if field == nil {
+			if remain.IsValid() {
+				remainPatch[k] = patch[k]
+			} else {
+				unused = append(unused, k)
+			}
+			continue
+		}
+
+		fieldName := field.name
+		if name != "" {
+			fieldName = name + "." + fieldName
+		}
+
+		value, err := d.keyFieldInput(fieldName, field, patch[k])
+		if err == nil {
+			err = p.patchValue(fieldName, field.val, value)
+		}
+		if err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				return d.joinErrors(errs)
+			}
+		}
+	}
+
+	if len(remainPatch) > 0 {
+		if err := p.patchMap(name, remain, remainPatch, true); err != nil {
+			errs, _ = d.addError(errs, err)
+		}
+	}
+
+	sort.Strings(unused)
+	unused, ignored := d.ignoreUnusedKeys(name, unused)
+	d.recordIgnoredKeys(name, ignored)
+
+	if len(unused) > 0 {
+		if d.config.ErrorUnused {
+			errs, _ = d.addError(errs, unusedKeysError(name, unused, keyFieldNames(fields)))
+		} else if d.config.Metadata != nil {
+			suggestions := suggestFieldNames(unused, keyFieldNames(fields))
+			for _, key := range unused {
+				fieldSuggestions := suggestions[key]
+				if name != "" {
+					key = name + "." + key
+				}
+				d.config.Metadata.Unused = append(d.config.Metadata.Unused, key)
+				if len(fieldSuggestions) > 0 {
+					d.config.Metadata.Suggestions[key] = fieldSuggestions
+				}
+			}
+		}
+	}
+
+	return d.joinErrors(errs)
+}
+
+// patchKeyPath applies the value of a patch found at keys onto the fields
+// with a key path starting with keys. Objects are followed into the fields
+// with longer key paths, and null resets all of them. The keys that no field
+// reads are returned by their key path, together with their values.
+func (p *patcher) patchKeyPath(name string, fields []keyField, keys []string, value interface{}) (map[string]interface{}, error) {
+	d := p.decoder
+	unused := make(map[string]interface{})
+
+	var errs []error
+	for i := range fields {
+		field := &fields[i]
+		if field.path == nil || !d.matchKeyPath(field.path, keys) {
+			continue
+		}
+
+		fieldName := field.name
+		if name != "" {
+			fieldName = name + "." + fieldName
+		}
+
+		value, err := d.keyFieldInput(fieldName, field, value)
+		if err == nil {
+			err = p.patchValue(fieldName, field.val, value)
+		}
+		return unused, err
+	}
+
+	object, ok := value.(map[string]interface{})
+	if value == nil {
+		// Null removes the whole object, and so resets every field in it.
+		for i := range fields {
+			field := &fields[i]
+			if len(field.path) <= len(keys) || !d.matchKeyPath(field.path[:len(keys)], keys) {
+				continue
+			}
+
+			fieldName := field.name
+			if name != "" {
+				fieldName = name + "." + fieldName
+			}
+			if err := p.patchValue(fieldName, field.val, nil); err != nil {
+				var stop bool
+				if errs, stop = d.addError(errs, err); stop {
+					break
+				}
+			}
+		}
+		return unused, d.joinErrors(errs)
+	}
+	if !ok {
+		unused[strings.Join(keys, d.config.KeyDelimiter)] = value
+		return unused, nil
+	}
+
+	objectKeys := make([]string, 0, len(object))
+	for k := range object {
+		objectKeys = append(objectKeys, k)
+	}
+	sort.Strings(objectKeys)
+
+	for _, k := range objectKeys {
+		nestedKeys := append(keys[:len(keys):len(keys)], k)
+		if !d.hasKeyPathPrefix(fields, nestedKeys) && !d.hasKeyPath(fields, nestedKeys) {
+			unused[strings.Join(nestedKeys, d.config.KeyDelimiter)] = object[k]
+			continue
+		}
+
+		nestedUnused, err := p.patchKeyPath(name, fields, nestedKeys, object[k])
+		if err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
+		}
+		for key, v := range nestedUnused {
+			unused[key] = v
+		}
+	}
+
+	return unused, d.joinErrors(errs)
+}
+
+// hasKeyPath reports whether one of fields has the key path keys.
+func (d *Decoder) hasKeyPath(fields []keyField, keys []string) bool {
+	for _, field := range fields {
+		if field.path != nil && d.matchKeyPath(field.path, keys) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasKeyPathPrefix reports whether one of fields has a key path longer than
+// keys that starts with them.
+func (d *Decoder) hasKeyPathPrefix(fields []keyField, keys []string) bool {
+	for _, field := range fields {
+		if len(field.path) > len(keys) && d.matchKeyPath(field.path[:len(keys)], keys) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patchMap merges the patch into a map. The keys of "remain" fields are
+// named like struct fields in the changed paths.
+func (p *patcher) patchMap(name string, val reflect.Value, patch map[string]interface{}, remain bool) error {
+	d := p.decoder
+	valType := val.Type()
+	if kind := valType.Key().Kind(); kind != reflect.String && kind != reflect.Interface {
+		return newDecodeError(name, fmt.Errorf("needs a map with string keys, has %q keys", kind))
+	}
+
+	if val.IsNil() {
+		val.Set(reflect.MakeMap(valType))
+	}
+
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, k := range keys {
+		fieldName := name + "[" + k + "]"
+		if remain {
+			fieldName = k
+			if name != "" {
+				fieldName = name + "." + k
+			}
+		}
+
+		key := reflect.ValueOf(k).Convert(valType.Key())
+		current := val.MapIndex(key)
+
+		if patch[k] == nil {
+			if current.IsValid() {
+				val.SetMapIndex(key, reflect.Value{})
+				p.changed = append(p.changed, fieldName)
+			}
+			continue
+		}
+
+		// Map elements aren't addressable, so they are patched in a copy.
+		elem := reflect.New(valType.Elem()).Elem()
+		if current.IsValid() {
+			elem.Set(current)
+		}
+
+		if err := p.patchValue(fieldName, elem, patch[k]); err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
+			continue
+		}
+
+		if !current.IsValid() {
+			p.changed = append(p.changed, fieldName)
+		}
+		val.SetMapIndex(key, elem)
+	}
+
+	return d.joinErrors(errs)
+}
+
+// patchValue applies a single value of a patch onto val.
+func (p *patcher) patchValue(name string, val reflect.Value, value interface{}) error {
+	if value == nil {
+		if !val.IsZero() {
+			val.Set(reflect.Zero(val.Type()))
+			p.changed = append(p.changed, name)
+		}
+		return nil
+	}
+
+	if object, ok := value.(map[string]interface{}); ok && p.mergeable(val) {
+		return p.patch(name, val, object)
+	}
+
+	return p.replace(name, val, value)
+}
+
+// mergeable reports whether a patch object is merged into val rather than
+// replacing it.
+func (p *patcher) mergeable(val reflect.Value) bool {
+	typ := val.Type()
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	case reflect.Interface:
+		// Interfaces are merged into only if they are empty or hold an
+		// object, otherwise the patch replaces them.
+		if val.Kind() == reflect.Interface {
+			_, ok := val.Interface().(map[string]interface{})
+			return ok || val.IsNil()
+		}
+	}
+
+	return false
+}
+
+// replace decodes value into val from scratch, as the merge patch replaces
+// the current value. On error val is left as it was.
+func (p *patcher) replace(name string, val reflect.Value, value interface{}) error {
+	decoded := reflect.New(val.Type()).Elem()
+	if err := p.decoder.decode(name, value, decoded); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(val.Interface(), decoded.Interface()) {
+		val.Set(decoded)
+		p.changed = append(p.changed, name)
+	}
+
+	return nil
+}
+
+// patchObject merges a patch into a generic object, as held by interface
+// values, following RFC 7396 to the letter.
+func (p *patcher) patchObject(name string, target, patch map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(target)+len(patch))
+	for k, v := range target {
+		result[k] = v
+	}
+
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fieldName := name + "[" + k + "]"
+		current, exists := result[k]
+
+		switch v := patch[k].(type) {
+		case nil:
+			if exists {
+				delete(result, k)
+				p.changed = append(p.changed, fieldName)
+			}
+		case map[string]interface{}:
+			object, _ := current.(map[string]interface{})
+			if object == nil {
+				p.changed = append(p.changed, fieldName)
+			}
+			result[k] = p.patchObject(fieldName, object, v)
+		default:
+			if !exists || !reflect.DeepEqual(current, v) {
+				result[k] = v
+				p.changed = append(p.changed, fieldName)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,162 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type patchTLS struct {
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+type PatchCommon struct {
+	Region string `mapstructure:"region"`
+}
+
+type patchConfig struct {
+	PatchCommon `mapstructure:",squash"`
+
+	Name    string                 `mapstructure:"name"`
+	Timeout time.Duration          `mapstructure:"timeout"`
+	Hosts   []string               `mapstructure:"hosts"`
+	TLS     *patchTLS              `mapstructure:"tls"`
+	Labels  map[string]string      `mapstructure:"labels"`
+	Meta    interface{}            `mapstructure:"meta"`
+	Extra   map[string]interface{} `mapstructure:",remain"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Parallel()
+
+	target := patchConfig{
+		PatchCommon: PatchCommon{Region: "eu"},
+		Name:        "app",
+		Timeout:     time.Second,
+		Hosts:       []string{"a", "b", "c"},
+		TLS:         &patchTLS{Cert: "a.pem", Key: "a.key"},
+		Labels:      map[string]string{"env": "prod", "team": "core"},
+		Meta:        map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}},
+	}
+
+	patch := map[string]interface{}{
+		"region":  "us",
+		"timeout": "5s",
+		"hosts":   []interface{}{"d"},
+		"tls":     map[string]interface{}{"cert": "b.pem", "key": nil},
+		"labels":  map[string]interface{}{"team": nil, "owner": "ops"},
+		"meta":    map[string]interface{}{"a": nil, "b": map[string]interface{}{"d": 3}},
+		"debug":   true,
+	}
+
+	changed, err := ApplyMergePatch(&target, patch, &DecoderConfig{
+		DecodeHook: StringToTimeDurationHookFunc(),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := patchConfig{
+		PatchCommon: PatchCommon{Region: "us"},
+		Name:        "app",
+		Timeout:     5 * time.Second,
+		Hosts:       []string{"d"},
+		TLS:         &patchTLS{Cert: "b.pem"},
+		Labels:      map[string]string{"env": "prod", "owner": "ops"},
+		Meta:        map[string]interface{}{"b": map[string]interface{}{"c": 2, "d": 3}},
+		Extra:       map[string]interface{}{"debug": true},
+	}
+	if !reflect.DeepEqual(target, expected) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expected, target)
+	}
+
+	expectedChanged := []string{
+		"debug",
+		"hosts",
+		"labels[owner]",
+		"labels[team]",
+		"meta[a]",
+		"meta[b][d]",
+		"region",
+		"timeout",
+		"tls.cert",
+		"tls.key",
+	}
+	if !reflect.DeepEqual(changed, expectedChanged) {
+		t.Fatalf("changed paths expected: %#v\ngot: %#v", expectedChanged, changed)
+	}
+
+	// Applying the same patch again changes nothing.
+	changed, err = ApplyMergePatch(&target, patch, &DecoderConfig{
+		DecodeHook: StringToTimeDurationHookFunc(),
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(changed) != 0 {
+		t.Fatalf("expected no changes, got %#v", changed)
+	}
+}
+
+func TestApplyMergePatch_Null(t *testing.T) {
+	t.Parallel()
+
+	target := patchConfig{
+		Name:  "app",
+		TLS:   &patchTLS{Cert: "a.pem"},
+		Hosts: []string{"a"},
+	}
+
+	changed, err := ApplyMergePatch(&target, map[string]interface{}{
+		"tls":   nil,
+		"hosts": nil,
+		"meta":  nil,
+	}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := patchConfig{Name: "app"}
+	if !reflect.DeepEqual(target, expected) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expected, target)
+	}
+
+	if expectedChanged := []string{"hosts", "tls"}; !reflect.DeepEqual(changed, expectedChanged) {
+		t.Fatalf("changed paths expected: %#v\ngot: %#v", expectedChanged, changed)
+	}
+}
+
+func TestApplyMergePatch_Errors(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Name string `mapstructure:"name"`
+		Port int    `mapstructure:"port"`
+	}
+
+	target := Config{Name: "app", Port: 80}
+	patch := map[string]interface{}{"name": "new", "port": "x", "unknown": 1}
+
+	_, err := ApplyMergePatch(&target, patch, &DecoderConfig{ErrorUnused: true, Atomic: true})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if expected := []string{"port", ""}; !reflect.DeepEqual(errorNames(err), expected) {
+		t.Fatalf("expected errors for %v, got %v (%s)", expected, errorNames(err), err)
+	}
+	if target != (Config{Name: "app", Port: 80}) {
+		t.Fatalf("expected target to be untouched, got %#v", target)
+	}
+
+	changed, err := ApplyMergePatch(&target, patch, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if target != (Config{Name: "new", Port: 80}) {
+		t.Fatalf("expected only the name to change, got %#v", target)
+	}
+	if expected := []string{"name"}; !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("changed paths expected: %#v\ngot: %#v", expected, changed)
+	}
+}
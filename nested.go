@@ -0,0 +1,151 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagKeyPath returns the keys of the nested location a field with the tag
+// name keyName is decoded from, or nil if keyName is a plain key.
+func (d *Decoder) tagKeyPath(keyName string) []string {
+	if d.config.KeyDelimiter == "" || !strings.Contains(keyName, d.config.KeyDelimiter) {
+		return nil
+	}
+
+	return strings.Split(keyName, d.config.KeyDelimiter)
+}
+
+// nestedKeys records the keys of the maps nested in the input that were
+// read by fields with a key path, keyed by the actual keys of the input. A
+// nil value means the whole value of the key was read.
+type nestedKeys map[string]nestedKeys
+
+// add records that the value at keys was read.
+func (n nestedKeys) add(keys []string) {
+	for i, key := range keys {
+		sub, ok := n[key]
+		if ok && sub == nil {
+			// An outer value was read as a whole already.
+			return
+		}
+		if i == len(keys)-1 {
+			n[key] = nil
+			return
+		}
+		if !ok {
+			sub = make(nestedKeys)
+			n[key] = sub
+		}
+		n = sub
+	}
+}
+
+// lookupKeyPath finds the value at path in dataVal, following nested maps
+// and matching their keys the way lookupMapKey does. It returns the actual
+// keys leading to the value.
+func (d *Decoder) lookupKeyPath(dataVal reflect.Value, dataValKeys map[reflect.Value]struct{}, path []string) ([]string, reflect.Value) {
+	keys := make([]string, len(path))
+
+	rawMapKey, rawMapVal := d.lookupMapKey(dataVal, dataValKeys, path[0])
+	if !rawMapVal.IsValid() {
+		return nil, reflect.Value{}
+	}
+	keys[0] = rawMapKey.Interface().(string)
+
+	for i, key := range path[1:] {
+		m := reflect.Indirect(rawMapVal)
+		if m.Kind() == reflect.Interface {
+			m = reflect.Indirect(m.Elem())
+		}
+		if m.Kind() != reflect.Map {
+			return nil, reflect.Value{}
+		}
+		if kind := m.Type().Key().Kind(); kind != reflect.String && kind != reflect.Interface {
+			return nil, reflect.Value{}
+		}
+
+		mKeys := make(map[reflect.Value]struct{}, m.Len())
+		for _, k := range m.MapKeys() {
+			mKeys[k] = struct{}{}
+		}
+
+		rawMapKey, rawMapVal = d.lookupMapKey(m, mKeys, key)
+		if !rawMapVal.IsValid() {
+			return nil, reflect.Value{}
+		}
+		keys[i+1] = rawMapKey.Interface().(string)
+	}
+
+	return keys, rawMapVal
+}
+
+// unusedNestedKeys adds the keys of the maps nested in val that weren't
+// read according to used to unused, joined to prefix with the
+// KeyDelimiter, together with their values.
+func (d *Decoder) unusedNestedKeys(prefix string, val reflect.Value, used nestedKeys, unused map[string]interface{}) {
+	if used == nil {
+		return
+	}
+
+	val = reflect.Indirect(val)
+	if val.Kind() == reflect.Interface {
+		val = reflect.Indirect(val.Elem())
+	}
+	if val.Kind() != reflect.Map {
+		return
+	}
+
+	for _, k := range val.MapKeys() {
+		key := fmt.Sprint(k.Interface())
+		fullKey := prefix + d.config.KeyDelimiter + key
+
+		sub, ok := used[key]
+		if !ok {
+			unused[fullKey] = val.MapIndex(k).Interface()
+			continue
+		}
+		d.unusedNestedKeys(fullKey, val.MapIndex(k), sub, unused)
+	}
+}
+
+// setKeyPath puts v into valMap at the nested location path, creating the
+// maps along the way with the type of valMap. f is the field v comes from.
+func (d *Decoder) setKeyPath(name string, f reflect.StructField, valMap reflect.Value, path []string, v reflect.Value) error {
+	m := valMap
+	for i, key := range path[:len(path)-1] {
+		k := reflect.ValueOf(key)
+		next := m.MapIndex(k)
+		if next.IsValid() && next.Kind() == reflect.Interface {
+			next = next.Elem()
+		}
+
+		if !next.IsValid() {
+			next = reflect.MakeMap(valMap.Type())
+			if !next.Type().AssignableTo(m.Type().Elem()) {
+				return newDecodeError(
+					name+"."+f.Name,
+					fmt.Errorf("cannot nest key %q in map value field of type %q", strings.Join(path, d.config.KeyDelimiter), m.Type().Elem()),
+				)
+			}
+			m.SetMapIndex(k, next)
+		} else if next.Kind() != reflect.Map || !k.Type().AssignableTo(next.Type().Key()) {
+			return newDecodeError(
+				name+"."+f.Name,
+				fmt.Errorf("cannot nest key %q in %q, which is not a map", strings.Join(path, d.config.KeyDelimiter), strings.Join(path[:i+1], d.config.KeyDelimiter)),
+			)
+		}
+
+		m = next
+	}
+
+	if !v.Type().AssignableTo(m.Type().Elem()) {
+		return newDecodeError(
+			name+"."+f.Name,
+			fmt.Errorf("cannot assign type %q to map value field of type %q", v.Type(), m.Type().Elem()),
+		)
+	}
+	m.SetMapIndex(reflect.ValueOf(path[len(path)-1]), v)
+
+	return nil
+}
@@ -0,0 +1,308 @@
+package mapstructure
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type NestedKeysConfig struct {
+	Host  string `mapstructure:"database.connection.host"`
+	Port  int    `mapstructure:"database.connection.port"`
+	Name  string `mapstructure:"database.name"`
+	Debug bool   `mapstructure:"debug"`
+}
+
+func TestDecode_keyDelimiter(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{
+				"HOST": "localhost",
+				"port": 5432,
+			},
+			"name": "app",
+		},
+		"debug": true,
+	}
+
+	var result NestedKeysConfig
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: ".", ErrorUnused: true, ErrorUnset: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := NestedKeysConfig{Host: "localhost", Port: 5432, Name: "app", Debug: true}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_keyDelimiterUnused(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{
+				"host":    "localhost",
+				"timeout": 5,
+			},
+			"user": "admin",
+		},
+		"extra": 1,
+	}
+
+	var result NestedKeysConfig
+	var md Metadata
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: ".", Metadata: &md, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedUnused := []string{"database.connection.timeout", "database.user", "extra"}
+	sort.Strings(md.Unused)
+	if !reflect.DeepEqual(md.Unused, expectedUnused) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+
+	expectedUnset := []string{"database.connection.port", "database.name", "debug"}
+	sort.Strings(md.Unset)
+	if !reflect.DeepEqual(md.Unset, expectedUnset) {
+		t.Fatalf("bad unset: %#v", md.Unset)
+	}
+
+	result = NestedKeysConfig{}
+	decoder, err = NewDecoder(&DecoderConfig{
+		KeyDelimiter:     ".",
+		ErrorUnused:      true,
+		IgnoreUnusedKeys: []string{"database.*.timeout"},
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil || err.Error() != "decoding failed due to the following error(s):\n\n'' has invalid keys: database.user (did you mean database.name?), extra" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecode_keyDelimiterRemain(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Host  string                 `mapstructure:"database.connection.host"`
+		Extra map[string]interface{} `mapstructure:",remain"`
+	}
+
+	input := map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{
+				"host":    "localhost",
+				"timeout": 5,
+			},
+			"user": "admin",
+		},
+		"extra": 1,
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: ".", ErrorUnused: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Config{
+		Host: "localhost",
+		Extra: map[string]interface{}{
+			"database.connection.timeout": 5,
+			"database.user":               "admin",
+			"extra":                       1,
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_keyDelimiterEncode(t *testing.T) {
+	t.Parallel()
+
+	input := NestedKeysConfig{Host: "localhost", Port: 5432, Name: "app", Debug: true}
+
+	var result map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: ".", Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{
+				"host": "localhost",
+				"port": 5432,
+			},
+			"name": "app",
+		},
+		"debug": true,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// Decoding a struct into another goes through the nested maps too.
+	var roundTrip NestedKeysConfig
+	decoder, err = NewDecoder(&DecoderConfig{KeyDelimiter: ".", ErrorUnused: true, Result: &roundTrip})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(roundTrip, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, roundTrip)
+	}
+}
+
+func TestDecode_keyDelimiterEncodeConflict(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Database string `mapstructure:"database"`
+		Host     string `mapstructure:"database.host"`
+	}
+
+	var result map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: ".", Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(Config{Database: "db", Host: "localhost"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDecode_keyDelimiterUnset(t *testing.T) {
+	t.Parallel()
+
+	// Without a KeyDelimiter, the tag is a single key.
+	input := map[string]interface{}{
+		"database.name": "flat",
+		"database":      map[string]interface{}{"name": "nested"},
+	}
+
+	var result NestedKeysConfig
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result.Name != "flat" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestGetSet_keyDelimiter(t *testing.T) {
+	t.Parallel()
+
+	config := &DecoderConfig{KeyDelimiter: "."}
+
+	var result NestedKeysConfig
+	if err := Set(&result, "database.connection.host", "h", config); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := Set(&result, "Database.Connection.Port", "5432", &DecoderConfig{KeyDelimiter: ".", WeaklyTypedInput: true}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := NestedKeysConfig{Host: "h", Port: 5432}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Set() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	v, err := Get(result, "database.connection.host", config)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != "h" {
+		t.Fatalf("Get() expected: %#v\ngot: %#v", "h", v)
+	}
+
+	_, err = Get(result, "database.connection.user", config)
+	if err == nil || err.Error() != `'' has no field "database"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestApplyMergePatch_keyDelimiter(t *testing.T) {
+	t.Parallel()
+
+	result := NestedKeysConfig{Host: "localhost", Port: 5432, Name: "app"}
+	patch := map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{"host": "remote"},
+			"name":       nil,
+		},
+	}
+
+	changed, err := ApplyMergePatch(&result, patch, &DecoderConfig{KeyDelimiter: ".", ErrorUnused: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := NestedKeysConfig{Host: "remote", Port: 5432}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	expectedChanged := []string{"database.connection.host", "database.name"}
+	if !reflect.DeepEqual(changed, expectedChanged) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expectedChanged, changed)
+	}
+
+	patch = map[string]interface{}{
+		"database": map[string]interface{}{
+			"connection": map[string]interface{}{"timeout": 5},
+		},
+	}
+	_, err = ApplyMergePatch(&result, patch, &DecoderConfig{KeyDelimiter: ".", ErrorUnused: true})
+	if err == nil || !strings.HasPrefix(err.Error(), "'' has invalid keys: database.connection.timeout") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without a key that fields with a key path start with, the nested
+	// fields can't be reached.
+	type Remain struct {
+		Host  string                 `mapstructure:"database.connection.host"`
+		Extra map[string]interface{} `mapstructure:",remain"`
+	}
+
+	var remain Remain
+	if _, err := ApplyMergePatch(&remain, patch, &DecoderConfig{KeyDelimiter: "."}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedRemain := Remain{Extra: map[string]interface{}{"database.connection.timeout": 5}}
+	if !reflect.DeepEqual(remain, expectedRemain) {
+		t.Fatalf("ApplyMergePatch() expected: %#v\ngot: %#v", expectedRemain, remain)
+	}
+}
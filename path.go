@@ -0,0 +1,488 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Get returns the value found at path in obj. Paths are written the way
+// decoding errors name values: struct fields by their mapstructure tag
+// name, joined by dots, and map keys and slice indexes in brackets, for
+// example "servers[1].tls.cert" or "labels[env]". Map keys can also be
+// written with a dot. Fields of squashed embedded structs are found as if
+// they were fields of the outer struct, and with a KeyDelimiter, fields
+// with a key path are found by the keys of their path.
+//
+// The tags are read using config. If config is nil, the defaults are used.
+func Get(obj any, path string, config *DecoderConfig) (any, error) {
+	c := DecoderConfig{WeaklyTypedInput: true}
+	if config != nil {
+		c = *config
+	}
+	c.Result = &obj
+
+	decoder, err := NewDecoder(&c)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	val := reflect.ValueOf(obj)
+	name := ""
+	for len(segments) > 0 {
+		for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+			if val.IsNil() {
+				return nil, newDecodeError(name, fmt.Errorf("is nil"))
+			}
+			val = val.Elem()
+		}
+
+		var n int
+		if val, n, err = decoder.pathElem(name, val, segments); err != nil {
+			return nil, err
+		}
+		name = joinPath(name, segments[:n]...)
+		segments = segments[n:]
+	}
+
+	return val.Interface(), nil
+}
+
+// Set decodes value into the field found at path in obj, which must be a
+// pointer. Paths are resolved like Get does. Nil pointers and maps along
+// the path are allocated, and an index one past the end of a slice appends
+// to it.
+//
+// The value replaces the current one and goes through the same decoding and
+// hooks as Decode, using config. If config is nil, WeaklyTypedInput is
+// enabled so that strings, such as those given on a command line, are
+// converted into the type of the field.
+func Set(obj any, path string, value any, config *DecoderConfig) error {
+	c := DecoderConfig{WeaklyTypedInput: true}
+	if config != nil {
+		c = *config
+	}
+	c.Result = obj
+
+	decoder, err := NewDecoder(&c)
+	if err != nil {
+		return err
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	return decoder.setPath("", reflect.ValueOf(obj).Elem(), segments, value)
+}
+
+// pathSegment is a single step of a path: a field name or map key, or a
+// bracketed map key or slice index.
+type pathSegment struct {
+	key     string
+	bracket bool
+}
+
+// parsePath splits a path into its segments.
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := path
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %q: missing ']'", path)
+			}
+			segments = append(segments, pathSegment{key: rest[1:end], bracket: true})
+			rest = rest[end+1:]
+			if rest != "" && rest[0] == '.' {
+				rest = rest[1:]
+				if rest == "" {
+					return nil, fmt.Errorf("invalid path %q: trailing '.'", path)
+				}
+			}
+
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+			if rest != "" && rest[0] == '.' {
+				rest = rest[1:]
+				if rest == "" {
+					return nil, fmt.Errorf("invalid path %q: trailing '.'", path)
+				}
+			}
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: empty path", path)
+	}
+
+	return segments, nil
+}
+
+// joinPath appends segments to the name of a value the way decoding names
+// nested values.
+func joinPath(name string, segments ...pathSegment) string {
+	for _, segment := range segments {
+		switch {
+		case segment.bracket:
+			name = name + "[" + segment.key + "]"
+		case name == "":
+			name = segment.key
+		default:
+			name = name + "." + segment.key
+		}
+	}
+	return name
+}
+
+// pathElem returns the value the leading segments refer to within val,
+// which must not be a pointer or interface, together with the number of
+// segments used. Only fields with a key path use more than one.
+func (d *Decoder) pathElem(name string, val reflect.Value, segments []pathSegment) (reflect.Value, int, error) {
+	segment := segments[0]
+	switch val.Kind() {
+	case reflect.Struct:
+		fields, remain := d.structKeyFields(val, false)
+		if field, n := d.findPathField(fields, segments); field != nil {
+			return field.val, n, nil
+		}
+		if remain.IsValid() {
+			return d.pathElem(name, remain, segments)
+		}
+		return reflect.Value{}, 0, newDecodeError(name, fmt.Errorf("has no field %q", segment.key))
+
+	case reflect.Map:
+		key, err := d.pathMapKey(name, val.Type(), segment)
+		if err != nil {
+			return reflect.Value{}, 0, err
+		}
+		elem := val.MapIndex(key)
+		if !elem.IsValid() {
+			return reflect.Value{}, 0, newDecodeError(joinPath(name, segment), fmt.Errorf("not found"))
+		}
+		return elem, 1, nil
+
+	case reflect.Slice, reflect.Array:
+		i, err := pathIndex(name, segment)
+		if err != nil {
+			return reflect.Value{}, 0, err
+		}
+		if i >= val.Len() {
+			return reflect.Value{}, 0, newDecodeError(name, fmt.Errorf("index %d out of range with length %d", i, val.Len()))
+		}
+		return val.Index(i), 1, nil
+	}
+
+	return reflect.Value{}, 0, newDecodeError(name, fmt.Errorf("cannot look up %q in type %s", segment.key, val.Type()))
+}
+
+// setPath decodes value into the value at segments within val, which must
+// be settable.
+func (d *Decoder) setPath(name string, val reflect.Value, segments []pathSegment, value interface{}) error {
+	if len(segments) == 0 {
+		decoded := reflect.New(val.Type()).Elem()
+		if err := d.decode(name, value, decoded); err != nil {
+			return err
+		}
+		val.Set(decoded)
+		return nil
+	}
+
+	segment := segments[0]
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return d.setPath(name, val.Elem(), segments, value)
+
+	case reflect.Interface:
+		if val.IsNil() {
+			return newDecodeError(name, fmt.Errorf("is nil"))
+		}
+
+		// The value held by an interface can't be set, so a copy of it is
+		// updated and stored back.
+		elem := reflect.New(val.Elem().Type()).Elem()
+		elem.Set(val.Elem())
+		if err := d.setPath(name, elem, segments, value); err != nil {
+			return err
+		}
+		val.Set(elem)
+		return nil
+
+	case reflect.Struct:
+		fields, remain := d.structKeyFields(val, true)
+		if field, n := d.findPathField(fields, segments); field != nil {
+			fieldName := joinPath(name, segments[:n]...)
+			if len(segments) == n {
+				var err error
+				if value, err = d.keyFieldInput(fieldName, field, value); err != nil {
+					return err
+				}
+			}
+			return d.setPath(fieldName, field.val, segments[n:], value)
+		}
+		if remain.IsValid() {
+			return d.setPath(name, remain, segments, value)
+		}
+		return newDecodeError(name, fmt.Errorf("has no field %q", segment.key))
+
+	case reflect.Map:
+		key, err := d.pathMapKey(name, val.Type(), segment)
+		if err != nil {
+			return err
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+
+		// Map elements aren't addressable, so they are updated in a copy.
+		elem := reflect.New(val.Type().Elem()).Elem()
+		if current := val.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := d.setPath(joinPath(name, segment), elem, segments[1:], value); err != nil {
+			return err
+		}
+		val.SetMapIndex(key, elem)
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		i, err := pathIndex(name, segment)
+		if err != nil {
+			return err
+		}
+		if val.Kind() == reflect.Slice && i == val.Len() {
+			val.Set(reflect.Append(val, reflect.Zero(val.Type().Elem())))
+		}
+		if i >= val.Len() {
+			return newDecodeError(name, fmt.Errorf("index %d out of range with length %d", i, val.Len()))
+		}
+		return d.setPath(joinPath(name, segment), val.Index(i), segments[1:], value)
+	}
+
+	return newDecodeError(name, fmt.Errorf("cannot look up %q in type %s", segment.key, val.Type()))
+}
+
+// pathMapKey decodes the key of a segment into the key type of a map.
+func (d *Decoder) pathMapKey(name string, mapType reflect.Type, segment pathSegment) (reflect.Value, error) {
+	key := reflect.New(mapType.Key()).Elem()
+	if err := d.decode(joinPath(name, segment), segment.key, key); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return key, nil
+}
+
+// pathIndex parses the index of a segment.
+func pathIndex(name string, segment pathSegment) (int, error) {
+	i, err := strconv.Atoi(segment.key)
+	if err != nil || !segment.bracket || i < 0 {
+		return 0, newDecodeError(name, fmt.Errorf("invalid index %q", segment.key))
+	}
+
+	return i, nil
+}
+
+// keyField is a struct field together with the names it can be looked up
+// by as a map key, its tag name and aliases, and the options of its tag.
+// path holds the keys of its key path, if it has one.
+type keyField struct {
+	val     reflect.Value
+	name    string
+	names   []string
+	options []string
+	path    []string
+}
+
+// keyFieldInput returns value shaped for decoding into the field, the way
+// decodeStructFromMap does for the "keyby" option.
+func (d *Decoder) keyFieldInput(name string, field *keyField, value interface{}) (interface{}, error) {
+	if keyBy := tagKeyBy(field.options); keyBy != "" && value != nil {
+		return d.keyByInput(name, keyBy, value, field.val)
+	}
+
+	return value, nil
+}
+
+// structKeyFields collects the fields of val the way decodeStructFromMap
+// does, including those of squashed structs, together with the "remain"
+// field. Nil pointers to squashed structs are allocated if alloc is set and
+// skipped otherwise.
+func (d *Decoder) structKeyFields(val reflect.Value, alloc bool) ([]keyField, reflect.Value) {
+	var fields []keyField
+	var remain reflect.Value
+
+	structs := []reflect.Value{val}
+	prefixes := []string{""}
+	for len(structs) > 0 {
+		structVal := structs[0]
+		structs = structs[1:]
+		prefix := prefixes[0]
+		prefixes = prefixes[1:]
+
+		structType := structVal.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			fieldType := structType.Field(i)
+			fieldVal := structVal.Field(i)
+			if fieldType.PkgPath != "" {
+				continue
+			}
+
+			tagValue := fieldType.Tag.Get(d.config.TagName)
+			tagParts := strings.Split(tagValue, ",")
+			if tagParts[0] == "-" {
+				continue
+			}
+
+			squash := d.config.Squash && fieldType.Anonymous &&
+				(fieldVal.Kind() == reflect.Struct || fieldVal.Kind() == reflect.Ptr && fieldVal.Type().Elem().Kind() == reflect.Struct)
+			isRemain := false
+			for _, tag := range tagParts[1:] {
+				if tag == d.config.SquashTagOption {
+					squash = true
+				}
+				if tag == "remain" {
+					isRemain = true
+				}
+			}
+
+			if squash {
+				if fieldVal.Kind() == reflect.Ptr {
+					if fieldVal.IsNil() {
+						if !alloc || !fieldVal.CanSet() {
+							continue
+						}
+						fieldVal.Set(reflect.New(fieldVal.Type().Elem()))
+					}
+					fieldVal = fieldVal.Elem()
+				}
+				if fieldVal.Kind() == reflect.Struct {
+					structs = append(structs, fieldVal)
+					prefixes = append(prefixes, prefix+tagPrefix(tagParts[1:]))
+				}
+				continue
+			}
+
+			if isRemain {
+				remain = fieldVal
+				continue
+			}
+
+			if tagValue == "" && d.config.IgnoreUntaggedFields {
+				continue
+			}
+
+			fieldName := prefix + fieldType.Name
+			if tagParts[0] != "" {
+				fieldName = prefix + tagParts[0]
+			}
+
+			names := []string{fieldName}
+			for _, alias := range tagAliases(tagParts[1:]) {
+				names = append(names, prefix+alias)
+			}
+
+			fields = append(fields, keyField{
+				val:     fieldVal,
+				name:    fieldName,
+				names:   names,
+				options: tagParts[1:],
+				path:    d.tagKeyPath(fieldName),
+			})
+		}
+	}
+
+	return fields, remain
+}
+
+// keyFieldNames returns all the names fields can be looked up by.
+func keyFieldNames(fields []keyField) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.names...)
+	}
+
+	return names
+}
+
+// findPathField returns the field that the leading segments of a path
+// refer to, together with the number of segments used: one for a field
+// found by its name, or the length of its key path.
+func (d *Decoder) findPathField(fields []keyField, segments []pathSegment) (*keyField, int) {
+	if field := d.findKeyField(fields, segments[0].key); field != nil {
+		return field, 1
+	}
+
+	keys := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.bracket {
+			break
+		}
+		keys = append(keys, segment.key)
+	}
+
+	for i := range fields {
+		if path := fields[i].path; path != nil && len(path) <= len(keys) && d.matchKeyPath(path, keys[:len(path)]) {
+			return &fields[i], len(path)
+		}
+	}
+
+	return nil, 0
+}
+
+// matchKeyPath reports whether keys match the key path of a field, using
+// MatchName for each of them.
+func (d *Decoder) matchKeyPath(path, keys []string) bool {
+	if len(path) != len(keys) {
+		return false
+	}
+
+	for i, key := range keys {
+		if !d.config.MatchName(key, path[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findKeyField returns the field that key refers to, preferring exact
+// matches over those found with MatchName, or nil if there is none.
+func (d *Decoder) findKeyField(fields []keyField, key string) *keyField {
+	for i := range fields {
+		for _, n := range fields[i].names {
+			if n == key {
+				return &fields[i]
+			}
+		}
+	}
+
+	for i := range fields {
+		for _, n := range fields[i].names {
+			if d.config.MatchName(key, n) {
+				return &fields[i]
+			}
+		}
+	}
+
+	return nil
+}
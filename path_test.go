@@ -0,0 +1,151 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PathCommon struct {
+	Region string `mapstructure:"region"`
+}
+
+type pathPool struct {
+	Max     int           `mapstructure:"max"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type pathDatabase struct {
+	Host string    `mapstructure:"host"`
+	Pool *pathPool `mapstructure:"pool"`
+}
+
+type pathServer struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+}
+
+type pathConfig struct {
+	PathCommon `mapstructure:",squash"`
+
+	DB      pathDatabase           `mapstructure:"db"`
+	Servers []pathServer           `mapstructure:"servers"`
+	Labels  map[string]string      `mapstructure:"labels"`
+	Ports   map[int]string         `mapstructure:"ports"`
+	Extra   map[string]interface{} `mapstructure:",remain"`
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	config := &pathConfig{
+		PathCommon: PathCommon{Region: "eu"},
+		DB:         pathDatabase{Host: "db", Pool: &pathPool{Max: 10}},
+		Servers:    []pathServer{{Host: "a", Port: 80}, {Host: "b", Port: 443}},
+		Labels:     map[string]string{"env": "prod"},
+		Ports:      map[int]string{80: "http"},
+		Extra:      map[string]interface{}{"debug": true},
+	}
+
+	cases := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"region", "eu"},
+		{"db.pool.max", 10},
+		{"DB.Pool.Max", 10},
+		{"db.pool", &pathPool{Max: 10}},
+		{"servers[1].port", 443},
+		{"servers[0]", pathServer{Host: "a", Port: 80}},
+		{"labels[env]", "prod"},
+		{"labels.env", "prod"},
+		{"ports[80]", "http"},
+		{"debug", true},
+	}
+
+	for _, tc := range cases {
+		actual, err := Get(config, tc.path, nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.path, err)
+		}
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Fatalf("%s: expected %#v, got %#v", tc.path, tc.expected, actual)
+		}
+	}
+
+	errorCases := []struct {
+		path string
+		err  string
+	}{
+		{"db.missing", `'db' has no field "missing"`},
+		{"servers[2]", "'servers' index 2 out of range with length 2"},
+		{"servers[x]", `'servers' invalid index "x"`},
+		{"labels[team]", "'labels[team]' not found"},
+		{"region.x", `'region' cannot look up "x" in type string`},
+		{"db..host", `invalid path "db..host": empty key`},
+		{"servers[1", `invalid path "servers[1": missing ']'`},
+	}
+
+	for _, tc := range errorCases {
+		_, err := Get(config, tc.path, nil)
+		if err == nil || err.Error() != tc.err {
+			t.Fatalf("%s: expected error %q, got %v", tc.path, tc.err, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	var config pathConfig
+
+	values := []struct {
+		path  string
+		value interface{}
+	}{
+		{"region", "us"},
+		{"db.pool.max", "20"},
+		{"db.pool.timeout", "5s"},
+		{"servers[0].host", "a"},
+		{"servers[0].port", "8080"},
+		{"servers[1]", map[string]interface{}{"host": "b"}},
+		{"labels[env]", "prod"},
+		{"ports[443]", "https"},
+		{"debug", "true"},
+	}
+
+	for _, v := range values {
+		err := Set(&config, v.path, v.value, &DecoderConfig{
+			WeaklyTypedInput: true,
+			DecodeHook:       StringToTimeDurationHookFunc(),
+		})
+		if err != nil {
+			t.Fatalf("%s: err: %s", v.path, err)
+		}
+	}
+
+	expected := pathConfig{
+		PathCommon: PathCommon{Region: "us"},
+		DB:         pathDatabase{Pool: &pathPool{Max: 20, Timeout: 5 * time.Second}},
+		Servers:    []pathServer{{Host: "a", Port: 8080}, {Host: "b"}},
+		Labels:     map[string]string{"env": "prod"},
+		Ports:      map[int]string{443: "https"},
+		Extra:      map[string]interface{}{"debug": "true"},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("Set() expected: %#v\ngot: %#v", expected, config)
+	}
+
+	err := Set(&config, "servers[0].port", "x", nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "'servers[0].port' ") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Servers[0].Port != 8080 {
+		t.Fatalf("expected port to be unchanged, got %d", config.Servers[0].Port)
+	}
+
+	if err := Set(&config, "servers[5].port", 1, nil); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,178 @@
+package mapstructure
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+)
+
+// pathPattern matches the names of values. Patterns are written like the
+// paths of Get, with each segment being a glob as understood by
+// path.Match, and "**" standing for any number of segments, including
+// none. Bracketed segments and dotted ones match alike, so "servers[*]"
+// matches "servers[0]" and "servers.0".
+type pathPattern []pathSegment
+
+// compilePathPattern parses pattern.
+func compilePathPattern(pattern string) (pathPattern, error) {
+	segments, err := parsePath(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, segment := range segments {
+		if _, err := path.Match(segment.key, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	return segments, nil
+}
+
+// match reports whether p matches the segments of a name.
+func (p pathPattern) match(segments []pathSegment) bool {
+	if len(p) == 0 {
+		return len(segments) == 0
+	}
+
+	if p[0].key == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if p[1:].match(segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(p[0].key, segments[0].key); !ok {
+		return false
+	}
+
+	return p[1:].match(segments[1:])
+}
+
+// compileIgnoreUnusedKeys parses the IgnoreUnusedKeys patterns.
+func compileIgnoreUnusedKeys(patterns []string) ([]pathPattern, error) {
+	compiled := make([]pathPattern, len(patterns))
+	for i, pattern := range patterns {
+		p, err := compilePathPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("IgnoreUnusedKeys: %w", err)
+		}
+		compiled[i] = p
+	}
+
+	return compiled, nil
+}
+
+// ignoreUnusedKeys splits the unused keys of the struct at name into those
+// that are still unused and those matching IgnoreUnusedKeys.
+func (d *Decoder) ignoreUnusedKeys(name string, keys []string) (unused, ignored []string) {
+	if len(d.ignoreUnused) == 0 || len(keys) == 0 {
+		return keys, nil
+	}
+
+	// The root has no segments, which parsePath reports as an error.
+	segments, _ := parsePath(name)
+	for _, key := range keys {
+		keySegments := segments[:len(segments):len(segments)]
+		if keyPath := d.tagKeyPath(key); keyPath != nil {
+			// Unused keys of nested maps are reported by their key path.
+			for _, k := range keyPath {
+				keySegments = append(keySegments, pathSegment{key: k})
+			}
+		} else {
+			keySegments = append(keySegments, pathSegment{key: key})
+		}
+		if d.isIgnoredUnusedKey(keySegments) {
+			ignored = append(ignored, key)
+		} else {
+			unused = append(unused, key)
+		}
+	}
+
+	return unused, ignored
+}
+
+func (d *Decoder) isIgnoredUnusedKey(segments []pathSegment) bool {
+	for _, p := range d.ignoreUnused {
+		if p.match(segments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordIgnoredKeys adds the keys of the struct at name that were ignored
+// through IgnoreUnusedKeys to the metadata.
+func (d *Decoder) recordIgnoredKeys(name string, ignored []string) {
+	if d.config.Metadata == nil {
+		return
+	}
+
+	for _, key := range ignored {
+		if name != "" {
+			key = name + "." + key
+		}
+		d.config.Metadata.Ignored = append(d.config.Metadata.Ignored, key)
+	}
+}
+
+// pathHook is a hook of PathHooks together with its compiled pattern.
+type pathHook struct {
+	pattern pathPattern
+	hook    func(from reflect.Value, to reflect.Value) (interface{}, error)
+}
+
+// compilePathHooks parses the patterns of PathHooks, ordering the hooks by
+// their patterns.
+func compilePathHooks(hooks map[string]DecodeHookFunc) ([]pathHook, error) {
+	patterns := make([]string, 0, len(hooks))
+	for pattern := range hooks {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	compiled := make([]pathHook, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := compilePathPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("PathHooks: %w", err)
+		}
+		if hooks[pattern] == nil {
+			continue
+		}
+		compiled = append(compiled, pathHook{pattern: p, hook: cachedDecodeHook(hooks[pattern])})
+	}
+
+	return compiled, nil
+}
+
+// runPathHooks runs the PathHooks matching the value at name.
+func (d *Decoder) runPathHooks(name string, from, to reflect.Value) (interface{}, error) {
+	input := from.Interface()
+
+	// The root has no segments, which parsePath reports as an error.
+	segments, _ := parsePath(name)
+	for _, h := range d.pathHooks {
+		if !h.pattern.match(segments) {
+			continue
+		}
+
+		var err error
+		if input, err = h.hook(from, to); err != nil {
+			return nil, err
+		}
+		from = reflect.ValueOf(input)
+		if !from.IsValid() {
+			return nil, nil
+		}
+	}
+
+	return input, nil
+}
@@ -0,0 +1,343 @@
+package mapstructure
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPathPattern_match(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"$schema", "$schema", true},
+		{"$schema", "nested.$schema", false},
+		{"**.x-*", "x-vendor", true},
+		{"**.x-*", "servers[0].x-vendor", true},
+		{"**.x-*", "servers[0].name", false},
+		{"servers[*]._comment", "servers[3]._comment", true},
+		{"servers[*]._comment", "servers._comment", false},
+		{"servers.*._comment", "servers[3]._comment", true},
+		{"servers[*]._comment", "servers[3].tls._comment", false},
+		{"servers.**._comment", "servers[3].tls._comment", true},
+		{"a?c", "abc", true},
+		{"a?c", "abbc", false},
+	}
+
+	for _, tc := range cases {
+		p, err := compilePathPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		segments, err := parsePath(tc.name)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if got := p.match(segments); got != tc.match {
+			t.Errorf("%q matching %q: expected %t, got %t", tc.pattern, tc.name, tc.match, got)
+		}
+	}
+}
+
+type IgnoreUnusedServer struct {
+	Name string `mapstructure:"name"`
+}
+
+type IgnoreUnusedConfig struct {
+	Servers []IgnoreUnusedServer `mapstructure:"servers"`
+}
+
+func TestDecode_ignoreUnusedKeys(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"$schema":  "https://example.com/schema.json",
+		"x-vendor": true,
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "_comment": "primary", "x-weight": 3},
+			map[string]interface{}{"name": "b", "other": true},
+		},
+	}
+
+	var result IgnoreUnusedConfig
+	decoder, err := NewDecoder(&DecoderConfig{
+		ErrorUnused:      true,
+		IgnoreUnusedKeys: []string{"$schema", "**.x-*", "servers[*]._comment"},
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "'servers[1]' has invalid keys: other") || strings.Count(err.Error(), "invalid keys") != 1 {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := IgnoreUnusedConfig{Servers: []IgnoreUnusedServer{{Name: "a"}, {Name: "b"}}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_ignoreUnusedKeysMetadata(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"$schema": "https://example.com/schema.json",
+		"servers": []interface{}{
+			map[string]interface{}{"name": "a", "_comment": "primary", "other": true},
+		},
+	}
+
+	var result IgnoreUnusedConfig
+	var md Metadata
+	decoder, err := NewDecoder(&DecoderConfig{
+		Metadata:         &md,
+		IgnoreUnusedKeys: []string{"$schema", "servers[*]._comment"},
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedIgnored := []string{"servers[0]._comment", "$schema"}
+	if !reflect.DeepEqual(md.Ignored, expectedIgnored) {
+		t.Fatalf("bad ignored: %#v", md.Ignored)
+	}
+
+	expectedUnused := []string{"servers[0].other"}
+	if !reflect.DeepEqual(md.Unused, expectedUnused) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+}
+
+func TestDecode_ignoreUnusedKeysStruct(t *testing.T) {
+	t.Parallel()
+
+	type Source struct {
+		Name    string `mapstructure:"name"`
+		Comment string `mapstructure:"_comment"`
+	}
+
+	var result IgnoreUnusedServer
+	decoder, err := NewDecoder(&DecoderConfig{
+		ErrorUnused:      true,
+		IgnoreUnusedKeys: []string{"_comment"},
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(Source{Name: "a", Comment: "primary"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result.Name != "a" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestNewDecoder_invalidIgnoreUnusedKeys(t *testing.T) {
+	t.Parallel()
+
+	var result IgnoreUnusedConfig
+	_, err := NewDecoder(&DecoderConfig{
+		IgnoreUnusedKeys: []string{"servers[*"},
+		Result:           &result,
+	})
+	if err == nil || !strings.Contains(err.Error(), "IgnoreUnusedKeys") {
+		t.Fatalf("expected IgnoreUnusedKeys error, got %v", err)
+	}
+
+	_, err = NewDecoder(&DecoderConfig{
+		IgnoreUnusedKeys: []string{"x-\\"},
+		Result:           &result,
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid pattern") {
+		t.Fatalf("expected invalid pattern error, got %v", err)
+	}
+}
+
+type PathHookLimit struct {
+	Rate int    `mapstructure:"rate"`
+	Name string `mapstructure:"name"`
+}
+
+type PathHookConfig struct {
+	Limits map[string]PathHookLimit `mapstructure:"limits"`
+	Label  string                   `mapstructure:"label"`
+}
+
+// rateHook parses rates written like "100/s".
+func rateHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	s, ok := data.(string)
+	if !ok {
+		return data, nil
+	}
+
+	return strings.TrimSuffix(s, "/s"), nil
+}
+
+func TestDecode_pathHooks(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"limits": map[string]interface{}{
+			"api": map[string]interface{}{"rate": "100/s", "name": "1/s"},
+		},
+		"label": "5/s",
+	}
+
+	var seen []string
+	var result PathHookConfig
+	decoder, err := NewDecoder(&DecoderConfig{
+		PathHooks: map[string]DecodeHookFunc{
+			"limits.*.rate": DecodeHookFuncType(rateHook),
+		},
+		DecodeHook: func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+			if s, ok := data.(string); ok {
+				seen = append(seen, s)
+			}
+			return data, nil
+		},
+		WeaklyTypedInput: true,
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := PathHookConfig{
+		Limits: map[string]PathHookLimit{"api": {Rate: 100, Name: "1/s"}},
+		Label:  "5/s",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// The DecodeHook sees the result of the path hook.
+	for _, s := range seen {
+		if s == "100/s" {
+			t.Fatalf("DecodeHook ran before the path hook: %#v", seen)
+		}
+	}
+}
+
+func TestDecode_pathHooksOrder(t *testing.T) {
+	t.Parallel()
+
+	appendHook := func(suffix string) DecodeHookFuncType {
+		return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+			return data.(string) + suffix, nil
+		}
+	}
+
+	type Server struct {
+		Name string `mapstructure:"name"`
+	}
+
+	type Config struct {
+		Servers []Server `mapstructure:"servers"`
+	}
+
+	input := map[string]interface{}{
+		"servers": []interface{}{map[string]interface{}{"name": "a"}},
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		PathHooks: map[string]DecodeHookFunc{
+			"servers[*].name":  appendHook("-1"),
+			"**.name":          appendHook("-0"),
+			"servers[1].name":  appendHook("-unused"),
+			"servers.0.name":   appendHook("-2"),
+			"servers[*].other": nil,
+		},
+		Result: &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if result.Servers[0].Name != "a-0-2-1" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestDecode_pathHooksError(t *testing.T) {
+	t.Parallel()
+
+	var result PathHookConfig
+	decoder, err := NewDecoder(&DecoderConfig{
+		PathHooks: map[string]DecodeHookFunc{
+			"label": DecodeHookFuncType(func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+				return nil, errors.New("bad label")
+			}),
+		},
+		Result: &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(map[string]interface{}{"label": "x"})
+	if err == nil || !strings.Contains(err.Error(), "'label' bad label") {
+		t.Fatalf("expected error, got %v", err)
+	}
+
+	_, err = NewDecoder(&DecoderConfig{
+		PathHooks: map[string]DecodeHookFunc{"limits[": nil},
+		Result:    &result,
+	})
+	if err == nil || !strings.Contains(err.Error(), "PathHooks") {
+		t.Fatalf("expected PathHooks error, got %v", err)
+	}
+}
+
+func TestDecodeValues_pathHooks(t *testing.T) {
+	t.Parallel()
+
+	var result PathHookConfig
+	decoder, err := NewDecoder(&DecoderConfig{
+		PathHooks: map[string]DecodeHookFunc{
+			"limits.*.rate": DecodeHookFuncType(rateHook),
+		},
+		WeaklyTypedInput: true,
+		Result:           &result,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.DecodeValues(url.Values{"limits[api][rate]": {"7/s"}}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if result.Limits["api"].Rate != 7 {
+		t.Fatalf("bad: %#v", result)
+	}
+}
@@ -0,0 +1,165 @@
+package mapstructure
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type PrefixTLS struct {
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key,alias=private_key"`
+}
+
+type PrefixListener struct {
+	Addr string    `mapstructure:"addr"`
+	TLS  PrefixTLS `mapstructure:",squash,prefix=tls_"`
+}
+
+type PrefixServer struct {
+	PrefixListener `mapstructure:",squash"`
+	Admin          PrefixListener `mapstructure:",squash,prefix=admin_"`
+	Name           string         `mapstructure:"name"`
+}
+
+func TestDecode_squashPrefix(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"addr":                  ":443",
+		"tls_cert":              "server.crt",
+		"tls_private_key":       "server.key",
+		"admin_addr":            ":8443",
+		"admin_tls_cert":        "admin.crt",
+		"admin_tls_key":         "admin.key",
+		"name":                  "api",
+		"admin_tls_unknown_key": true,
+	}
+
+	var result PrefixServer
+	var md Metadata
+	decoder, err := NewDecoder(&DecoderConfig{Metadata: &md, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := PrefixServer{
+		PrefixListener: PrefixListener{Addr: ":443", TLS: PrefixTLS{Cert: "server.crt", Key: "server.key"}},
+		Admin:          PrefixListener{Addr: ":8443", TLS: PrefixTLS{Cert: "admin.crt", Key: "admin.key"}},
+		Name:           "api",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	if !reflect.DeepEqual(md.Unused, []string{"admin_tls_unknown_key"}) {
+		t.Fatalf("bad unused: %#v", md.Unused)
+	}
+	if !reflect.DeepEqual(md.Aliases, map[string]string{"tls_key": "tls_private_key"}) {
+		t.Fatalf("bad aliases: %#v", md.Aliases)
+	}
+}
+
+func TestDecode_squashPrefixEncode(t *testing.T) {
+	t.Parallel()
+
+	input := PrefixServer{
+		PrefixListener: PrefixListener{Addr: ":443", TLS: PrefixTLS{Cert: "server.crt", Key: "server.key"}},
+		Admin:          PrefixListener{Addr: ":8443", TLS: PrefixTLS{Cert: "admin.crt", Key: "admin.key"}},
+		Name:           "api",
+	}
+
+	var result map[string]interface{}
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"addr":           ":443",
+		"tls_cert":       "server.crt",
+		"tls_key":        "server.key",
+		"admin_addr":     ":8443",
+		"admin_tls_cert": "admin.crt",
+		"admin_tls_key":  "admin.key",
+		"name":           "api",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// Decoding into another struct goes through the prefixed keys too.
+	var roundTrip PrefixServer
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnused: true, ErrorUnset: true, Result: &roundTrip})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(roundTrip, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, roundTrip)
+	}
+}
+
+func TestDecode_squashPrefixCollisions(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		TLS     PrefixTLS `mapstructure:",squash,prefix=tls_"`
+		TLSCert string    `mapstructure:"tls_cert"`
+	}
+
+	input := map[string]interface{}{"tls_cert": "a.crt"}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionError, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil || !strings.Contains(err.Error(), `has conflicting fields for key "tls_cert": TLSCert, TLS.Cert`) {
+		t.Fatalf("expected collision error, got %v", err)
+	}
+
+	var m map[string]interface{}
+	decoder, err = NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionError, Result: &m})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(Config{TLS: PrefixTLS{Cert: "a.crt"}, TLSCert: "b.crt"})
+	if err == nil || !strings.Contains(err.Error(), `has conflicting fields for key "tls_cert"`) {
+		t.Fatalf("expected collision error, got %v", err)
+	}
+
+	result = Config{}
+	decoder, err = NewDecoder(&DecoderConfig{KeyCollisions: KeyCollisionShallowest, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if result.TLSCert != "a.crt" || result.TLS.Cert != "" {
+		t.Fatalf("bad: %#v", result)
+	}
+}
+
+func TestGet_squashPrefix(t *testing.T) {
+	t.Parallel()
+
+	server := PrefixServer{Admin: PrefixListener{TLS: PrefixTLS{Cert: "admin.crt"}}}
+
+	v, err := Get(server, "admin_tls_cert", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != "admin.crt" {
+		t.Fatalf("bad: %#v", v)
+	}
+}
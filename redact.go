@@ -0,0 +1,167 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultRedactionPlaceholder is the value that replaces sensitive fields
+// when Redaction.Placeholder is empty.
+const DefaultRedactionPlaceholder = "[REDACTED]"
+
+// Redaction configures how struct fields tagged with the "sensitive" option
+// are encoded into maps, for example:
+//
+//	Password string `mapstructure:"password,sensitive"`
+//
+// Sensitive fields are redacted wherever they appear: in nested and
+// squashed structs as well as in structs held by slices, arrays, maps and
+// interfaces. Slices and maps whose elements may hold sensitive fields are
+// encoded as []interface{} and map[K]interface{}, with every struct
+// element turned into a map.
+type Redaction struct {
+	// Placeholder replaces the values of sensitive fields. It defaults to
+	// DefaultRedactionPlaceholder.
+	Placeholder string
+
+	// Remove leaves sensitive fields out of the map instead of replacing
+	// their values.
+	Remove bool
+}
+
+func (r *Redaction) placeholder() string {
+	if r.Placeholder == "" {
+		return DefaultRedactionPlaceholder
+	}
+
+	return r.Placeholder
+}
+
+// Redact converts a struct, or a pointer to one, into a map with the values
+// of all sensitive fields replaced by DefaultRedactionPlaceholder. It is
+// meant for logging and debugging output and returns nil if input can't be
+// converted.
+func Redact(input any) map[string]any {
+	result, err := Encode(input, &EncoderConfig{Redaction: &Redaction{}})
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// unredacted returns a Decoder like d that doesn't redact sensitive
+// fields. Redaction only applies to maps that are part of the result, not
+// to the maps structs are converted to on their way into other structs.
+func (d *Decoder) unredacted() *Decoder {
+	if d.config.Redaction == nil {
+		return d
+	}
+
+	config := *d.config
+	config.Redaction = nil
+
+	result := *d
+	result.config = &config
+	return &result
+}
+
+// redactValue returns v with the sensitive fields of the structs it holds
+// redacted. Values that can't hold sensitive fields are returned as is.
+func (d *Decoder) redactValue(name string, v reflect.Value) (reflect.Value, error) {
+	if !d.mayHoldSensitive(v.Type(), make(map[reflect.Type]bool)) {
+		return v, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return v, nil
+		}
+		return d.redactValue(name, v.Elem())
+
+	case reflect.Struct:
+		if d.isTupleStruct(v.Type()) {
+			tuple, err := d.tupleOutput(name, v)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.ValueOf(tuple), nil
+		}
+
+		m := make(map[string]interface{})
+		if err := d.decode(name, v.Interface(), reflect.ValueOf(&m).Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(m), nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v, nil
+		}
+
+		result := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := d.redactValue(name+"["+strconv.Itoa(i)+"]", v.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result[i] = elem.Interface()
+		}
+		return reflect.ValueOf(result), nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v, nil
+		}
+
+		mapType := reflect.MapOf(v.Type().Key(), reflect.TypeOf((*interface{})(nil)).Elem())
+		result := reflect.MakeMapWithSize(mapType, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			elem, err := d.redactValue(name+"["+fmt.Sprint(it.Key().Interface())+"]", it.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.SetMapIndex(it.Key(), elem)
+		}
+		return result, nil
+	}
+
+	return v, nil
+}
+
+// mayHoldSensitive reports whether values of typ may hold struct fields
+// tagged as sensitive. Interfaces may hold anything.
+func (d *Decoder) mayHoldSensitive(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return d.mayHoldSensitive(typ.Elem(), seen)
+	case reflect.Struct:
+		if seen[typ] {
+			return false
+		}
+		seen[typ] = true
+
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			tagValue := f.Tag.Get(d.config.TagName)
+			if hasTagOption(strings.Split(tagValue, ",")[1:], "sensitive") {
+				return true
+			}
+
+			if d.mayHoldSensitive(f.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
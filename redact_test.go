@@ -0,0 +1,195 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+)
+
+type RedactCredentials struct {
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password,sensitive"`
+}
+
+type redactConfig struct {
+	RedactCredentials `mapstructure:",squash"`
+
+	Name     string                       `mapstructure:"name"`
+	Token    *string                      `mapstructure:"token,omitempty,sensitive"`
+	Database RedactCredentials            `mapstructure:"database"`
+	Replicas []RedactCredentials          `mapstructure:"replicas"`
+	Services map[string]RedactCredentials `mapstructure:"services"`
+	Extra    interface{}                  `mapstructure:"extra"`
+	Ports    []int                        `mapstructure:"ports"`
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	token := "t0ken"
+	input := redactConfig{
+		RedactCredentials: RedactCredentials{User: "root", Password: "hunter2"},
+		Name:              "app",
+		Token:             &token,
+		Database:          RedactCredentials{User: "db", Password: "secret"},
+		Replicas:          []RedactCredentials{{User: "r1", Password: "p1"}},
+		Services:          map[string]RedactCredentials{"cache": {User: "c", Password: "p"}},
+		Extra:             &RedactCredentials{User: "x", Password: "y"},
+		Ports:             []int{80},
+	}
+
+	expected := map[string]interface{}{
+		"user":     "root",
+		"password": DefaultRedactionPlaceholder,
+		"name":     "app",
+		"token":    DefaultRedactionPlaceholder,
+		"database": map[string]interface{}{"user": "db", "password": DefaultRedactionPlaceholder},
+		"replicas": []interface{}{
+			map[string]interface{}{"user": "r1", "password": DefaultRedactionPlaceholder},
+		},
+		"services": map[string]interface{}{
+			"cache": map[string]interface{}{"user": "c", "password": DefaultRedactionPlaceholder},
+		},
+		"extra": map[string]interface{}{"user": "x", "password": DefaultRedactionPlaceholder},
+		"ports": []int{80},
+	}
+
+	actual := Redact(&input)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Redact() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	if input.Replicas[0].Password != "p1" || *input.Token != "t0ken" {
+		t.Fatalf("input was modified: %#v", input)
+	}
+}
+
+func TestDecoder_RedactionRemove(t *testing.T) {
+	t.Parallel()
+
+	input := redactConfig{
+		RedactCredentials: RedactCredentials{User: "root", Password: "hunter2"},
+		Database:          RedactCredentials{User: "db", Password: "secret"},
+	}
+
+	var actual map[string]interface{}
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result:    &actual,
+		Redaction: &Redaction{Remove: true},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"user":     "root",
+		"name":     "",
+		"database": map[string]interface{}{"user": "db"},
+		"replicas": []RedactCredentials(nil),
+		"services": map[string]RedactCredentials(nil),
+		"extra":    nil,
+		"ports":    []int(nil),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestEncode_RedactionPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	actual, err := Encode(RedactCredentials{User: "u", Password: "p"}, &EncoderConfig{
+		Redaction: &Redaction{Placeholder: "***"},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{"user": "u", "password": "***"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Encode() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	// Without redaction the values are kept.
+	actual, err = Encode(RedactCredentials{User: "u", Password: "p"}, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected = map[string]interface{}{"user": "u", "password": "p"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Encode() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestRedaction_structToStruct(t *testing.T) {
+	t.Parallel()
+
+	type Target struct {
+		Name     string
+		Password string `mapstructure:"password"`
+		Database struct {
+			User     string `mapstructure:"user"`
+			Password string `mapstructure:"password"`
+		} `mapstructure:"database"`
+		Replicas []RedactCredentials `mapstructure:"replicas"`
+	}
+
+	input := redactConfig{
+		RedactCredentials: RedactCredentials{User: "root", Password: "hunter2"},
+		Name:              "app",
+		Database:          RedactCredentials{User: "db", Password: "secret"},
+		Replicas:          []RedactCredentials{{User: "r1", Password: "p1"}},
+	}
+
+	var result Target
+	decoder, err := NewDecoder(&DecoderConfig{Redaction: &Redaction{}, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var expected Target
+	expected.Name = "app"
+	expected.Password = "hunter2"
+	expected.Database.User = "db"
+	expected.Database.Password = "secret"
+	expected.Replicas = []RedactCredentials{{User: "r1", Password: "p1"}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestRedact_optionNames(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Key      string `mapstructure:"key,alias=sensitive_key"`
+		Password string `mapstructure:"password,alias=pass,sensitive"`
+		Nested   struct {
+			Key string `mapstructure:"key,alias=insensitive"`
+		} `mapstructure:"nested"`
+	}
+
+	var input Config
+	input.Key = "k"
+	input.Password = "p"
+	input.Nested.Key = "n"
+
+	expected := map[string]interface{}{
+		"key":      "k",
+		"password": DefaultRedactionPlaceholder,
+		"nested":   map[string]interface{}{"key": "n"},
+	}
+
+	actual := Redact(input)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Redact() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
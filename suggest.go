@@ -0,0 +1,93 @@
+package mapstructure
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// suggestFieldNames returns the closest matching names for each of the
+// unused keys, keyed by key, or nil if none of them are close to any name.
+// names are the keys the fields of a struct are decoded from, including
+// their aliases.
+func suggestFieldNames(keys, names []string) map[string][]string {
+	var suggestions map[string][]string
+	for _, key := range keys {
+		if s := suggestFieldName(key, names); len(s) > 0 {
+			if suggestions == nil {
+				suggestions = make(map[string][]string)
+			}
+			suggestions[key] = s
+		}
+	}
+
+	return suggestions
+}
+
+// suggestFieldName returns the names closest to key by edit distance,
+// ignoring case, sorted. Names are only suggested when at most a third of
+// key, and at least one character, would need to change.
+func suggestFieldName(key string, names []string) []string {
+	limit := utf8.RuneCountInString(key) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	lowerKey := strings.ToLower(key)
+	best := limit + 1
+	var suggestions []string
+	for _, name := range names {
+		distance := levenshtein(lowerKey, strings.ToLower(name))
+		switch {
+		case distance < best:
+			best = distance
+			suggestions = append(suggestions[:0], name)
+		case distance == best:
+			suggestions = append(suggestions, name)
+		}
+	}
+
+	sort.Strings(suggestions)
+	return dedupeSorted(suggestions)
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = prev[j] + 1
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := prev[j-1] + cost; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// unusedKeysError returns the error for the sorted unused keys of the
+// struct at name, whose fields are decoded from names.
+func unusedKeysError(name string, keys, names []string) error {
+	return newDecodeError(name, &UnusedKeysError{
+		Keys:        keys,
+		Suggestions: suggestFieldNames(keys, names),
+	})
+}
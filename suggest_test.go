@@ -0,0 +1,155 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type SuggestServer struct {
+	Host    string
+	Port    int
+	Timeout int `mapstructure:"timeout,alias=timeout_seconds"`
+}
+
+type SuggestConfig struct {
+	SuggestServer `mapstructure:",squash"`
+	Name          string
+	Nested        SuggestServer `mapstructure:"nested"`
+	hidden        string
+}
+
+func TestDecode_unusedKeySuggestions(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"hots":            "localhost",
+		"nme":             "foo",
+		"timeout_second":  3,
+		"hiden":           "x",
+		"completely_else": true,
+	}
+
+	var result SuggestConfig
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnused: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := "decoding failed due to the following error(s):\n\n" +
+		"'' has invalid keys: completely_else, hiden, hots (did you mean Host?), nme (did you mean Name?), " +
+		"timeout_second (did you mean timeout_seconds?)"
+	if err.Error() != expected {
+		t.Fatalf("expected error:\n%s\ngot:\n%s", expected, err)
+	}
+
+	var unusedErr *UnusedKeysError
+	if !errors.As(err, &unusedErr) {
+		t.Fatalf("expected UnusedKeysError, got %T", err)
+	}
+
+	expectedErr := &UnusedKeysError{
+		Keys: []string{"completely_else", "hiden", "hots", "nme", "timeout_second"},
+		Suggestions: map[string][]string{
+			"hots":           {"Host"},
+			"nme":            {"Name"},
+			"timeout_second": {"timeout_seconds"},
+		},
+	}
+	if !reflect.DeepEqual(unusedErr, expectedErr) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expectedErr, unusedErr)
+	}
+}
+
+func TestDecode_unusedKeySuggestionsMetadata(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"hots":   "localhost",
+		"other":  true,
+		"nested": map[string]interface{}{"prot": 80},
+	}
+
+	var result SuggestConfig
+	var md Metadata
+	decoder, err := NewDecoder(&DecoderConfig{Metadata: &md, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string][]string{
+		"hots":        {"Host"},
+		"nested.prot": {"Port"},
+	}
+	if !reflect.DeepEqual(md.Suggestions, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, md.Suggestions)
+	}
+}
+
+func TestDecode_unusedKeySuggestionsStruct(t *testing.T) {
+	t.Parallel()
+
+	type Source struct {
+		Hots string
+		Port int
+	}
+
+	type Target struct {
+		Host string
+		Port int
+	}
+
+	var result Target
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnused: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(Source{Hots: "localhost", Port: 80})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var unusedErr *UnusedKeysError
+	if !errors.As(err, &unusedErr) {
+		t.Fatalf("expected UnusedKeysError, got %T", err)
+	}
+
+	expected := map[string][]string{"Hots": {"Host"}}
+	if !reflect.DeepEqual(unusedErr.Suggestions, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, unusedErr.Suggestions)
+	}
+}
+
+func TestSuggestFieldName(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"host", "port", "post", "name", "database_url"}
+
+	cases := []struct {
+		key      string
+		expected []string
+	}{
+		{"hots", []string{"host"}},
+		{"HOST", []string{"host"}},
+		{"pot", []string{"port", "post"}},
+		{"databse_url", []string{"database_url"}},
+		{"x", nil},
+		{"timeout", nil},
+	}
+
+	for _, tc := range cases {
+		if got := suggestFieldName(tc.key, names); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: expected %#v, got %#v", tc.key, tc.expected, got)
+		}
+	}
+}
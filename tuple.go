@@ -0,0 +1,282 @@
+package mapstructure
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tupleField is a field of a struct decoded from a tuple, with the key it
+// is decoded from in the map the tuple is turned into.
+type tupleField struct {
+	name     string
+	key      string
+	index    int
+	optional bool
+}
+
+// hasTagOption reports whether the options of a tag include option.
+func hasTagOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTupleMarker reports whether f is a blank field marking its struct as a
+// tuple, as in:
+//
+//	_ struct{} `mapstructure:",tuple"`
+func (d *Decoder) isTupleMarker(f reflect.StructField) bool {
+	return f.Name == "_" && hasTagOption(strings.Split(f.Tag.Get(d.config.TagName), ",")[1:], "tuple")
+}
+
+// isTupleStruct reports whether typ is a struct marked as a tuple.
+func (d *Decoder) isTupleStruct(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		if d.isTupleMarker(typ.Field(i)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tupleFields returns the fields of the struct type typ in the order of
+// their positions in a tuple. Fields take the position following the one
+// of the previous field, unless it is set with the "index" option.
+func (d *Decoder) tupleFields(typ reflect.Type) ([]tupleField, error) {
+	var fields []tupleField
+	byIndex := make(map[int]string)
+	next := 0
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagValue := f.Tag.Get(d.config.TagName)
+		if tagValue == "" && d.config.IgnoreUntaggedFields {
+			continue
+		}
+		tagParts := strings.Split(tagValue, ",")
+		if tagParts[0] == "-" {
+			continue
+		}
+
+		options := tagParts[1:]
+		if hasTagOption(options, d.config.SquashTagOption) || hasTagOption(options, "remain") ||
+			d.config.Squash && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			return nil, fmt.Errorf("field %s can't be squashed or remain in a tuple", f.Name)
+		}
+
+		field := tupleField{
+			name:     f.Name,
+			key:      f.Name,
+			index:    next,
+			optional: hasTagOption(options, "omitempty"),
+		}
+		if tagParts[0] != "" {
+			field.key = tagParts[0]
+		}
+		for _, option := range options {
+			if strings.HasPrefix(option, "index=") {
+				index, err := strconv.Atoi(strings.TrimPrefix(option, "index="))
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("field %s has invalid %q option", f.Name, option)
+				}
+				field.index = index
+			}
+		}
+
+		if other, ok := byIndex[field.index]; ok {
+			return nil, fmt.Errorf("fields %s and %s have the same tuple index %d", other, f.Name, field.index)
+		}
+		byIndex[field.index] = f.Name
+		next = field.index + 1
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// tupleSize returns the number of elements of the tuple for fields, and
+// how many of them are required.
+func tupleSize(fields []tupleField) (size, required int) {
+	for _, f := range fields {
+		if f.index >= size {
+			size = f.index + 1
+		}
+		if !f.optional && f.index >= required {
+			required = f.index + 1
+		}
+	}
+
+	return size, required
+}
+
+// tupleInput turns the slice or array dataVal into a map to decode into
+// the struct type typ, keyed by the fields at the positions of its
+// elements.
+func (d *Decoder) tupleInput(name string, dataVal reflect.Value, typ reflect.Type) (map[string]interface{}, error) {
+	fields, err := d.tupleFields(typ)
+	if err != nil {
+		return nil, newDecodeError(name, err)
+	}
+
+	size, required := tupleSize(fields)
+	if n := dataVal.Len(); n > size {
+		return nil, newDecodeError(name, fmt.Errorf("has %d elements, expected at most %d", n, size))
+	} else if n < required {
+		return nil, newDecodeError(name, fmt.Errorf("has %d elements, expected at least %d", n, required))
+	}
+
+	result := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		if f.index < dataVal.Len() {
+			result[f.key] = dataVal.Index(f.index).Interface()
+		}
+	}
+
+	return result, nil
+}
+
+// tupleOutput turns the struct v into a tuple. Its fields are decoded into
+// a map first, and optional fields that were omitted are left out at the
+// end of the tuple.
+func (d *Decoder) tupleOutput(name string, v reflect.Value) ([]interface{}, error) {
+	fields, err := d.tupleFields(v.Type())
+	if err != nil {
+		return nil, newDecodeError(name, err)
+	}
+
+	x := reflect.New(v.Type())
+	x.Elem().Set(v)
+
+	m := make(map[string]interface{})
+	if err := d.decode(name, x.Interface(), reflect.ValueOf(&m).Elem()); err != nil {
+		return nil, err
+	}
+
+	size, required := tupleSize(fields)
+	result := make([]interface{}, size)
+	present := make([]bool, size)
+	for _, f := range fields {
+		if value, ok := m[f.key]; ok {
+			result[f.index] = value
+			present[f.index] = true
+		}
+	}
+
+	for len(result) > required && !present[len(result)-1] {
+		result = result[:len(result)-1]
+	}
+
+	return result, nil
+}
+
+// tupleFieldInput converts the input of a field with the "tuple" option to
+// the shape of outVal: a slice is turned into a map keyed by the fields of
+// the struct outVal holds, and a slice of slices into a slice of such maps
+// when outVal holds a slice of structs. Any other input is returned as it
+// is.
+func (d *Decoder) tupleFieldInput(name string, input interface{}, outVal reflect.Value) (interface{}, error) {
+	outType := outVal.Type()
+	for outType.Kind() == reflect.Ptr {
+		outType = outType.Elem()
+	}
+
+	dataVal := reflect.Indirect(reflect.ValueOf(input))
+	if dataVal.Kind() == reflect.Interface {
+		dataVal = reflect.Indirect(dataVal.Elem())
+	}
+	if dataVal.Kind() != reflect.Slice && dataVal.Kind() != reflect.Array {
+		return input, nil
+	}
+
+	switch outType.Kind() {
+	case reflect.Struct:
+		return d.tupleInput(name, dataVal, outType)
+
+	case reflect.Slice, reflect.Array:
+		elemType := outType.Elem()
+		for elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			return input, nil
+		}
+
+		result := make([]interface{}, dataVal.Len())
+		for i := 0; i < dataVal.Len(); i++ {
+			elem := reflect.Indirect(dataVal.Index(i))
+			if elem.Kind() == reflect.Interface {
+				elem = reflect.Indirect(elem.Elem())
+			}
+			if elem.Kind() != reflect.Slice && elem.Kind() != reflect.Array {
+				result[i] = dataVal.Index(i).Interface()
+				continue
+			}
+
+			fields, err := d.tupleInput(name+"["+strconv.Itoa(i)+"]", elem, elemType)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = fields
+		}
+		return result, nil
+	}
+
+	return input, nil
+}
+
+// tupleFieldOutput converts the value of a field when a struct is decoded
+// into a map, the opposite way tupleFieldInput does. Tuple structs are
+// turned into tuples even without the "tuple" option.
+func (d *Decoder) tupleFieldOutput(name string, tuple bool, v reflect.Value) (reflect.Value, error) {
+	s := v
+	for s.Kind() == reflect.Ptr && !s.IsNil() {
+		s = s.Elem()
+	}
+
+	switch {
+	case s.Kind() == reflect.Struct && (tuple || d.isTupleStruct(s.Type())):
+		result, err := d.tupleOutput(name, s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(result), nil
+
+	case tuple && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v, nil
+		}
+
+		result := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem := reflect.Indirect(v.Index(i))
+			if elem.Kind() != reflect.Struct {
+				result[i] = v.Index(i).Interface()
+				continue
+			}
+
+			tuple, err := d.tupleOutput(name+"["+strconv.Itoa(i)+"]", elem)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result[i] = tuple
+		}
+		return reflect.ValueOf(result), nil
+	}
+
+	return v, nil
+}
@@ -0,0 +1,240 @@
+package mapstructure
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TupleEndpoint struct {
+	_      struct{} `mapstructure:",tuple"`
+	Host   string
+	Port   int
+	Secure bool `mapstructure:",omitempty"`
+}
+
+type TupleCoord struct {
+	Lat float64 `mapstructure:"lat"`
+	Lon float64 `mapstructure:"lon"`
+	Alt float64 `mapstructure:"alt,index=3,omitempty"`
+}
+
+type TupleConfig struct {
+	Endpoint TupleEndpoint `mapstructure:"endpoint"`
+	Origin   TupleCoord    `mapstructure:"origin,tuple"`
+	Path     []TupleCoord  `mapstructure:"path,tuple"`
+}
+
+func TestDecode_tuple(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"endpoint": []interface{}{"10.0.0.1", 8080, true},
+		"origin":   []interface{}{1.5, 2.5},
+		"path": []interface{}{
+			[]interface{}{1, 2},
+			[]interface{}{3, 4, nil, 5},
+			map[string]interface{}{"lat": 6, "lon": 7},
+		},
+	}
+
+	var result TupleConfig
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnused: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := TupleConfig{
+		Endpoint: TupleEndpoint{Host: "10.0.0.1", Port: 8080, Secure: true},
+		Origin:   TupleCoord{Lat: 1.5, Lon: 2.5},
+		Path: []TupleCoord{
+			{Lat: 1, Lon: 2},
+			{Lat: 3, Lon: 4, Alt: 5},
+			{Lat: 6, Lon: 7},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
+
+func TestDecode_tupleOptional(t *testing.T) {
+	t.Parallel()
+
+	var result TupleEndpoint
+	if err := Decode([]interface{}{"localhost", 80}, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := TupleEndpoint{Host: "localhost", Port: 80}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	// The blank field marking the tuple is never unset.
+	decoder, err := NewDecoder(&DecoderConfig{ErrorUnset: true, Result: &result})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode([]interface{}{"localhost", 80, true}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDecode_tupleErrors(t *testing.T) {
+	t.Parallel()
+
+	type Duplicate struct {
+		_ struct{} `mapstructure:",tuple"`
+		A string
+		B string `mapstructure:",index=0"`
+	}
+
+	type Squashed struct {
+		_             struct{} `mapstructure:",tuple"`
+		TupleEndpoint `mapstructure:",squash"`
+	}
+
+	cases := []struct {
+		name     string
+		input    interface{}
+		result   interface{}
+		expected string
+	}{
+		{
+			"too long",
+			[]interface{}{"localhost", 80, true, "extra"},
+			&TupleEndpoint{},
+			"'' has 4 elements, expected at most 3",
+		},
+		{
+			"too short",
+			[]interface{}{"localhost"},
+			&TupleEndpoint{},
+			"'' has 1 elements, expected at least 2",
+		},
+		{
+			"field",
+			map[string]interface{}{"origin": []interface{}{1}},
+			&TupleConfig{},
+			"decoding failed due to the following error(s):\n\n'origin' has 1 elements, expected at least 2",
+		},
+		{
+			"element",
+			map[string]interface{}{"path": []interface{}{[]interface{}{1, 2}, []interface{}{1, 2, 3, 4, 5}}},
+			&TupleConfig{},
+			"decoding failed due to the following error(s):\n\n'path[1]' has 5 elements, expected at most 4",
+		},
+		{
+			"duplicate index",
+			[]interface{}{"a"},
+			&Duplicate{},
+			"'' fields A and B have the same tuple index 0",
+		},
+		{
+			"squash",
+			[]interface{}{"a"},
+			&Squashed{},
+			"'' field TupleEndpoint can't be squashed or remain in a tuple",
+		},
+		{
+			"not a tuple",
+			[]interface{}{1.5, 2.5},
+			&TupleCoord{},
+			"'' expected a map or struct, got \"slice\"",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Decode(tc.input, tc.result)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if err.Error() != tc.expected {
+				t.Fatalf("expected error:\n%s\ngot:\n%s", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestDecode_tupleEncode(t *testing.T) {
+	t.Parallel()
+
+	input := TupleConfig{
+		Endpoint: TupleEndpoint{Host: "localhost", Port: 80},
+		Origin:   TupleCoord{Lat: 1, Lon: 2, Alt: 3},
+		Path:     []TupleCoord{{Lat: 4, Lon: 5}},
+	}
+
+	var result map[string]interface{}
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"endpoint": []interface{}{"localhost", 80},
+		"origin":   []interface{}{1.0, 2.0, nil, 3.0},
+		"path":     []interface{}{[]interface{}{4.0, 5.0}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+
+	var tuple []interface{}
+	if err := Decode(TupleEndpoint{Host: "localhost", Port: 80, Secure: true}, &tuple); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expectedTuple := []interface{}{"localhost", 80, true}
+	if !reflect.DeepEqual(tuple, expectedTuple) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expectedTuple, tuple)
+	}
+}
+
+func TestDecode_tupleStructToStruct(t *testing.T) {
+	t.Parallel()
+
+	var roundTrip TupleConfig
+	input := TupleConfig{
+		Endpoint: TupleEndpoint{Host: "localhost", Port: 80, Secure: true},
+		Origin:   TupleCoord{Lat: 1, Lon: 2},
+		Path:     []TupleCoord{{Lat: 3, Lon: 4, Alt: 5}},
+	}
+
+	var m map[string]interface{}
+	if err := Decode(input, &m); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := Decode(m, &roundTrip); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !reflect.DeepEqual(roundTrip, input) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", input, roundTrip)
+	}
+
+	type Target struct {
+		Endpoint []interface{} `mapstructure:"endpoint"`
+		Origin   []float64     `mapstructure:"origin"`
+	}
+
+	var result Target
+	if err := Decode(input, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Target{
+		Endpoint: []interface{}{"localhost", 80, true},
+		Origin:   []float64{1, 2},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Decode() expected: %#v\ngot: %#v", expected, result)
+	}
+}
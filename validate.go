@@ -0,0 +1,372 @@
+package mapstructure
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/CoverWhale/mapstructure/v2/internal/errors"
+)
+
+// ValidationRule checks a single decoded value against the parameter given
+// in the validate tag, for example "1" for "min=1". The value is never a
+// pointer or interface; nil pointers are only checked by "nonempty".
+type ValidationRule func(val reflect.Value, param string) error
+
+// defaultValidationRules are the rules available unless overridden by
+// DecoderConfig.ValidationRules.
+var defaultValidationRules = map[string]ValidationRule{
+	"min":      validateMin,
+	"max":      validateMax,
+	"len":      validateLen,
+	"oneof":    validateOneOf,
+	"pattern":  validatePattern,
+	"nonempty": validateNonEmpty,
+	"url":      validateURL,
+}
+
+// validateVisit is a pointer or map seen while validating, together with
+// its type since a struct and its first field share their address.
+type validateVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// validate checks the rules in the validate tags of val and everything it
+// contains. Paths are built the same way decoding builds them, so errors
+// point at the keys the input used.
+func (d *Decoder) validate(name string, val reflect.Value) error {
+	return d.validateValue(name, val, make(map[validateVisit]struct{}))
+}
+
+// validateValue validates val unless it was seen before, which keeps cycles
+// from recursing forever.
+func (d *Decoder) validateValue(name string, val reflect.Value, seen map[validateVisit]struct{}) error {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if val.IsNil() {
+			return nil
+		}
+
+		visit := validateVisit{ptr: val.Pointer(), typ: val.Type()}
+		if _, ok := seen[visit]; ok {
+			return nil
+		}
+		seen[visit] = struct{}{}
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		return d.validateValue(name, val.Elem(), seen)
+
+	case reflect.Struct:
+		return d.validateStruct(name, "", val, seen)
+
+	case reflect.Slice, reflect.Array:
+		var errs []error
+		for i := 0; i < val.Len(); i++ {
+			if err := d.validateValue(name+"["+strconv.Itoa(i)+"]", val.Index(i), seen); err != nil {
+				var stop bool
+				if errs, stop = d.addError(errs, err); stop {
+					break
+				}
+			}
+		}
+		return d.joinErrors(errs)
+
+	case reflect.Map:
+		keys := val.MapKeys()
+		sortMapKeys(keys)
+
+		var errs []error
+		for _, k := range keys {
+			fieldName := name + "[" + fmt.Sprint(k.Interface()) + "]"
+			if err := d.validateValue(fieldName, val.MapIndex(k), seen); err != nil {
+				var stop bool
+				if errs, stop = d.addError(errs, err); stop {
+					break
+				}
+			}
+		}
+		return d.joinErrors(errs)
+	}
+
+	return nil
+}
+
+// validateStruct validates the fields of the struct val. prefix is the
+// prefix of the keys of its fields, set by the "prefix" option of the
+// fields it is squashed into.
+func (d *Decoder) validateStruct(name, prefix string, val reflect.Value, seen map[validateVisit]struct{}) error {
+	var errs []error
+
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagValue := f.Tag.Get(d.config.TagName)
+		if tagValue == "" && d.config.IgnoreUntaggedFields {
+			continue
+		}
+
+		tagParts := strings.Split(tagValue, ",")
+		if tagParts[0] == "-" {
+			continue
+		}
+
+		fieldVal := val.Field(i)
+
+		squash := d.config.Squash && f.Anonymous
+		for _, tag := range tagParts[1:] {
+			if tag == d.config.SquashTagOption {
+				squash = true
+			}
+		}
+
+		if squash {
+			// Squashed structs may be behind pointers and interfaces.
+			squashed := fieldVal
+			for (squashed.Kind() == reflect.Ptr || squashed.Kind() == reflect.Interface) && !squashed.IsNil() {
+				squashed = squashed.Elem()
+			}
+
+			if squashed.Kind() == reflect.Struct {
+				if err := d.validateStruct(name, prefix+tagPrefix(tagParts[1:]), squashed, seen); err != nil {
+					var stop bool
+					if errs, stop = d.addError(errs, err); stop {
+						break
+					}
+				}
+				continue
+			}
+		}
+
+		// Fields are named after the keys they are decoded from, key
+		// paths included, as decodeStructFromMap names them.
+		fieldName := f.Name
+		if tagParts[0] != "" {
+			fieldName = tagParts[0]
+		}
+		fieldName = prefix + fieldName
+		if name != "" {
+			fieldName = name + "." + fieldName
+		}
+
+		if rules := f.Tag.Get(d.config.ValidateTagName); rules != "" {
+			if err := d.validateRules(fieldName, fieldVal, rules); err != nil {
+				var stop bool
+				if errs, stop = d.addError(errs, err); stop {
+					break
+				}
+			}
+		}
+
+		if err := d.validateValue(fieldName, fieldVal, seen); err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, err); stop {
+				break
+			}
+		}
+	}
+
+	return d.joinErrors(errs)
+}
+
+// validateRules checks val against the comma separated rules of a validate
+// tag. Since patterns may contain commas, "pattern" takes the rest of the
+// tag and must come last.
+func (d *Decoder) validateRules(name string, val reflect.Value, rules string) error {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			break
+		}
+		val = val.Elem()
+	}
+
+	var errs []error
+	for rules != "" {
+		var rule string
+		if strings.HasPrefix(rules, "pattern=") {
+			rule, rules = rules, ""
+		} else if index := strings.Index(rules, ","); index != -1 {
+			rule, rules = rules[:index], rules[index+1:]
+		} else {
+			rule, rules = rules, ""
+		}
+
+		ruleName, param, _ := strings.Cut(rule, "=")
+
+		fn, ok := d.config.ValidationRules[ruleName]
+		if !ok {
+			fn, ok = defaultValidationRules[ruleName]
+		}
+		if !ok {
+			var stop bool
+			if errs, stop = d.addError(errs, newDecodeError(name, fmt.Errorf("unknown validation rule %q", ruleName))); stop {
+				break
+			}
+			continue
+		}
+
+		// Nil values only fail the nonempty rule.
+		if (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && ruleName != "nonempty" {
+			continue
+		}
+
+		if err := fn(val, param); err != nil {
+			var stop bool
+			if errs, stop = d.addError(errs, newDecodeError(name, &ValidationError{
+				Rule:  ruleName,
+				Param: param,
+				Err:   err,
+			})); stop {
+				break
+			}
+		}
+	}
+
+	return d.joinErrors(errs)
+}
+
+// validationSize returns the number compared by min and max: the value of
+// numbers and the length of strings, slices, arrays and maps.
+func validationSize(val reflect.Value) (float64, error) {
+	switch getKind(val) {
+	case reflect.Int:
+		return float64(val.Int()), nil
+	case reflect.Uint:
+		return float64(val.Uint()), nil
+	case reflect.Float32:
+		return val.Float(), nil
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(val.Len()), nil
+	default:
+		return 0, fmt.Errorf("unsupported type %s", val.Type())
+	}
+}
+
+func validateMin(val reflect.Value, param string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return err
+	}
+
+	size, err := validationSize(val)
+	if err != nil {
+		return err
+	}
+
+	if size < limit {
+		return fmt.Errorf("must be at least %s", param)
+	}
+
+	return nil
+}
+
+func validateMax(val reflect.Value, param string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return err
+	}
+
+	size, err := validationSize(val)
+	if err != nil {
+		return err
+	}
+
+	if size > limit {
+		return fmt.Errorf("must be at most %s", param)
+	}
+
+	return nil
+}
+
+func validateLen(val reflect.Value, param string) error {
+	length, err := strconv.Atoi(param)
+	if err != nil {
+		return err
+	}
+
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		if val.Len() != length {
+			return fmt.Errorf("must have length %d, got %d", length, val.Len())
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+}
+
+func validateOneOf(val reflect.Value, param string) error {
+	s := fmt.Sprint(val.Interface())
+	for _, option := range strings.Fields(param) {
+		if s == option {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("must be one of %s", strings.Join(strings.Fields(param), ", "))
+}
+
+// validationPatterns caches the regular expressions of "pattern" rules,
+// which come from struct tags and are checked over and over.
+var validationPatterns sync.Map
+
+func validatePattern(val reflect.Value, param string) error {
+	if val.Kind() != reflect.String {
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+
+	var re *regexp.Regexp
+	if cached, ok := validationPatterns.Load(param); ok {
+		re = cached.(*regexp.Regexp)
+	} else {
+		var err error
+		if re, err = regexp.Compile(param); err != nil {
+			return err
+		}
+		validationPatterns.Store(param, re)
+	}
+
+	if !re.MatchString(val.String()) {
+		return fmt.Errorf("must match %s", param)
+	}
+
+	return nil
+}
+
+func validateNonEmpty(val reflect.Value, _ string) error {
+	if isEmptyValue(val) || val.IsZero() {
+		return errors.New("must not be empty")
+	}
+
+	return nil
+}
+
+func validateURL(val reflect.Value, _ string) error {
+	if val.Kind() != reflect.String {
+		return fmt.Errorf("unsupported type %s", val.Type())
+	}
+
+	u, err := url.Parse(val.String())
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return errors.New("must be an absolute URL")
+	}
+
+	return nil
+}
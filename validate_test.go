@@ -0,0 +1,205 @@
+package mapstructure
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder_Validate(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host string `mapstructure:"host" validate:"nonempty,pattern=^[a-z.]+$"`
+		Port int    `mapstructure:"port" validate:"min=1,max=65535"`
+	}
+
+	type Common struct {
+		Env string `validate:"oneof=dev prod"`
+	}
+
+	type Config struct {
+		Common   `mapstructure:",squash"`
+		Name     string            `mapstructure:"name" validate:"len=3"`
+		Endpoint *string           `mapstructure:"endpoint" validate:"url"`
+		Servers  []Server          `mapstructure:"servers" validate:"min=1"`
+		Backends map[string]Server `mapstructure:"backends"`
+	}
+
+	input := map[string]interface{}{
+		"env":      "test",
+		"name":     "ab",
+		"endpoint": "localhost",
+		"servers": []map[string]interface{}{
+			{"host": "a.example", "port": 80},
+			{"host": "b.example", "port": 443},
+			{"host": "C", "port": 70000},
+		},
+		"backends": map[string]interface{}{
+			"primary": map[string]interface{}{"port": 0},
+		},
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, Validate: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := []string{
+		"'Env' failed validation 'oneof=dev prod': must be one of dev, prod",
+		"'name' failed validation 'len=3': must have length 3, got 2",
+		"'endpoint' failed validation 'url': must be an absolute URL",
+		"'servers[2].host' failed validation 'pattern=^[a-z.]+$': must match ^[a-z.]+$",
+		"'servers[2].port' failed validation 'max=65535': must be at most 65535",
+		"'backends[primary].host' failed validation 'nonempty': must not be empty",
+		"'backends[primary].host' failed validation 'pattern=^[a-z.]+$': must match ^[a-z.]+$",
+		"'backends[primary].port' failed validation 'min=1': must be at least 1",
+	}
+	if !reflect.DeepEqual(expected, strings.Split(err.Error(), "\n")[2:]) {
+		t.Fatalf("unexpected error:\n%s", err)
+	}
+
+	var derr *DecodeError
+	var verr *ValidationError
+	if !errors.As(err, &derr) || !errors.As(err, &verr) {
+		t.Fatalf("expected DecodeError wrapping a ValidationError, got %#v", err)
+	}
+}
+
+func TestDecoder_ValidateValid(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Port     int     `validate:"min=1"`
+		Endpoint *string `validate:"url"`
+		Tags     []string
+	}
+
+	input := map[string]interface{}{"port": 8080}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, Validate: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDecoder_ValidationRules(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Count int    `validate:"even"`
+		Name  string `validate:"unknown"`
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{
+		Result:   &result,
+		Validate: true,
+		ValidationRules: map[string]ValidationRule{
+			"even": func(val reflect.Value, _ string) error {
+				if val.Int()%2 != 0 {
+					return errors.New("must be even")
+				}
+				return nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(map[string]interface{}{"count": 3})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if !strings.Contains(err.Error(), "'Count' failed validation 'even': must be even") {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.Contains(err.Error(), `'Name' unknown validation rule "unknown"`) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestDecoder_ValidateDisabled(t *testing.T) {
+	t.Parallel()
+
+	type Config struct {
+		Port int `validate:"min=1"`
+	}
+
+	var result Config
+	if err := Decode(map[string]interface{}{}, &result); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestDecoder_ValidatePaths(t *testing.T) {
+	t.Parallel()
+
+	type Listener struct {
+		Port int `mapstructure:"port" validate:"min=1"`
+	}
+
+	type Config struct {
+		Admin Listener `mapstructure:",squash,prefix=admin_"`
+		Host  string   `mapstructure:"db__host" validate:"nonempty"`
+	}
+
+	input := map[string]interface{}{
+		"admin_port": 0,
+		"db":         map[string]interface{}{"host": ""},
+	}
+
+	var result Config
+	decoder, err := NewDecoder(&DecoderConfig{KeyDelimiter: "__", Result: &result, Validate: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.Decode(input)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	expected := []string{
+		"'admin_port' failed validation 'min=1': must be at least 1",
+		"'db__host' failed validation 'nonempty': must not be empty",
+	}
+	if !reflect.DeepEqual(expected, strings.Split(err.Error(), "\n")[2:]) {
+		t.Fatalf("unexpected error:\n%s", err)
+	}
+}
+
+func TestDecoder_ValidateCycle(t *testing.T) {
+	t.Parallel()
+
+	type Node struct {
+		Name string `mapstructure:"name" validate:"nonempty"`
+		Next *Node  `mapstructure:"next"`
+	}
+
+	var result Node
+	result.Next = &result
+	decoder, err := NewDecoder(&DecoderConfig{Result: &result, Validate: true})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.Decode(map[string]interface{}{"name": "a"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
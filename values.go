@@ -0,0 +1,222 @@
+package mapstructure
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeValues decodes url.Values, such as a parsed query string or form
+// post, into output. It is the same as WeakDecode but understands
+// multi-valued keys and bracket notation. See Decoder.DecodeValues.
+func DecodeValues(values url.Values, output interface{}) error {
+	config := &DecoderConfig{
+		Metadata:         nil,
+		Result:           output,
+		WeaklyTypedInput: true,
+	}
+
+	decoder, err := NewDecoder(config)
+	if err != nil {
+		return err
+	}
+
+	return decoder.DecodeValues(values)
+}
+
+// DecodeValues decodes url.Values into the target pointer specified by the
+// configuration.
+//
+// Keys with a single value are decoded into scalar fields and keys with
+// multiple values are decoded into slices. If a key has multiple values
+// but the target is a scalar, the first value is used. Bracket notation
+// can be used to build nested maps and slices:
+//
+//	filter[status]=open           {"filter": {"status": "open"}}
+//	ids[]=1&ids[]=2               {"ids": ["1", "2"]}
+//	servers[0][host]=localhost    {"servers": [{"host": "localhost"}]}
+//
+// Elements keep their indexes, so a[0]=x&a[2]=y leaves the element at
+// index 1 zero. Indexes must be below 10000.
+//
+// Since every value is a string, WeaklyTypedInput should usually be
+// enabled. The package level DecodeValues does so. The hooks of the
+// configuration run after the values were matched to the shape of the
+// target, so they see plain strings for scalar fields, and so do PathHooks.
+func (d *Decoder) DecodeValues(values url.Values) error {
+	input, err := valuesToMap(values)
+	if err != nil {
+		return err
+	}
+
+	hook := DecodeHookFunc(valuesHookFunc())
+	if d.cachedDecodeHook != nil {
+		hook = ComposeDecodeHookFunc(hook, DecodeHookFuncValue(d.cachedDecodeHook))
+	}
+
+	vd := *d
+	vd.cachedDecodeHook = cachedDecodeHook(hook)
+
+	// Plans depend on the hooks, so the ones cached for d don't apply.
+	vd.structPlans = new(sync.Map)
+
+	// Path hooks run before the others, so they need the values shaped
+	// too.
+	vd.pathHooks = make([]pathHook, len(d.pathHooks))
+	for i, h := range d.pathHooks {
+		vd.pathHooks[i] = pathHook{
+			pattern: h.pattern,
+			hook:    cachedDecodeHook(ComposeDecodeHookFunc(valuesHookFunc(), DecodeHookFuncValue(h.hook))),
+		}
+	}
+
+	return vd.Decode(input)
+}
+
+// valuesToMap turns url.Values into a map, expanding bracket notation into
+// nested maps. All values are kept as []string and shaped to match the
+// target by valuesHookFunc during decoding.
+func valuesToMap(values url.Values) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]interface{})
+	for _, k := range keys {
+		path := parseValuesKey(k)
+
+		m := result
+		for _, segment := range path[:len(path)-1] {
+			switch v := m[segment].(type) {
+			case nil:
+				nested := make(map[string]interface{})
+				m[segment] = nested
+				m = nested
+			case map[string]interface{}:
+				m = v
+			default:
+				return nil, newDecodeError(k, fmt.Errorf("conflicts with value of %q", segment))
+			}
+		}
+
+		last := path[len(path)-1]
+		switch v := m[last].(type) {
+		case nil:
+			m[last] = append([]string(nil), values[k]...)
+		case []string:
+			m[last] = append(v, values[k]...)
+		default:
+			return nil, newDecodeError(k, fmt.Errorf("conflicts with nested keys of %q", last))
+		}
+	}
+
+	return result, nil
+}
+
+// parseValuesKey splits a key using bracket notation into its segments.
+// An empty trailing "[]" is dropped since it only marks a slice. Keys that
+// don't use well-formed bracket notation are returned as a single segment.
+func parseValuesKey(key string) []string {
+	idx := strings.IndexByte(key, '[')
+	if idx <= 0 || !strings.HasSuffix(key, "]") {
+		return []string{key}
+	}
+
+	path := []string{key[:idx]}
+	rest := key[idx:]
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end == -1 {
+			return []string{key}
+		}
+
+		segment := rest[1:end]
+		rest = rest[end+1:]
+		if segment == "" {
+			if rest != "" {
+				return []string{key}
+			}
+			break
+		}
+
+		path = append(path, segment)
+	}
+
+	return path
+}
+
+// maxValuesIndex bounds the indexes of bracket notation, since the slices
+// they are decoded into are as long as the largest index.
+const maxValuesIndex = 10000
+
+// valuesHookFunc returns a DecodeHookFunc that shapes the values produced by
+// valuesToMap to match the target: []string values are reduced to a single
+// string for scalar targets and maps with integer keys become slices for
+// slice and array targets, with the elements at their indexes.
+func valuesHookFunc() DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		switch v := data.(type) {
+		case []string:
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array:
+				if t.Elem().Kind() != reflect.Uint8 {
+					return v, nil
+				}
+			case reflect.Interface:
+				if len(v) != 1 {
+					return v, nil
+				}
+			}
+
+			if len(v) == 0 {
+				return "", nil
+			}
+
+			return v[0], nil
+
+		case map[string]interface{}:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return data, nil
+			}
+
+			length := 0
+			for k := range v {
+				i, err := strconv.Atoi(k)
+				if err != nil || i < 0 {
+					return data, nil
+				}
+				if i >= maxValuesIndex {
+					return nil, fmt.Errorf("index %d exceeds the limit of %d", i, maxValuesIndex-1)
+				}
+				if i >= length {
+					length = i + 1
+				}
+			}
+
+			// Elements keep their indexes. Missing ones are left nil and
+			// decoded as zero values.
+			result := make([]interface{}, length)
+			for k, elem := range v {
+				i, _ := strconv.Atoi(k)
+				if result[i] != nil {
+					return nil, fmt.Errorf("index %d is set more than once", i)
+				}
+				result[i] = elem
+			}
+
+			return result, nil
+		}
+
+		return data, nil
+	}
+}
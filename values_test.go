@@ -0,0 +1,200 @@
+package mapstructure
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeValues(t *testing.T) {
+	t.Parallel()
+
+	type Server struct {
+		Host string
+		Port int
+	}
+
+	type Request struct {
+		Query   string            `mapstructure:"q"`
+		Page    *int              `mapstructure:"page"`
+		Verbose bool              `mapstructure:"verbose"`
+		Tags    []string          `mapstructure:"tag"`
+		IDs     []int             `mapstructure:"ids"`
+		Filter  map[string]string `mapstructure:"filter"`
+		Servers []Server          `mapstructure:"servers"`
+		Sort    string            `mapstructure:"sort"`
+	}
+
+	values, err := url.ParseQuery(
+		"q=foo&page=2&verbose=true&tag=a&tag=b&ids[]=1&ids[]=2" +
+			"&filter[status]=open&filter[owner]=me" +
+			"&servers[1][host]=b&servers[0][host]=a&servers[0][port]=80" +
+			"&sort=name&sort=date",
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var actual Request
+	if err := DecodeValues(values, &actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	page := 2
+	expected := Request{
+		Query:   "foo",
+		Page:    &page,
+		Verbose: true,
+		Tags:    []string{"a", "b"},
+		IDs:     []int{1, 2},
+		Filter:  map[string]string{"status": "open", "owner": "me"},
+		Servers: []Server{{Host: "a", Port: 80}, {Host: "b"}},
+		Sort:    "name",
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeValues() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestDecoder_DecodeValues(t *testing.T) {
+	t.Parallel()
+
+	type Request struct {
+		Timeout time.Duration          `mapstructure:"timeout"`
+		Extra   map[string]interface{} `mapstructure:",remain"`
+	}
+
+	values := url.Values{
+		"timeout": {"5s"},
+		"a":       {"1"},
+		"b":       {"1", "2"},
+	}
+
+	var actual Request
+	decoder, err := NewDecoder(&DecoderConfig{
+		DecodeHook:       StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &actual,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if err := decoder.DecodeValues(values); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Request{
+		Timeout: 5 * time.Second,
+		Extra: map[string]interface{}{
+			"a": "1",
+			"b": []string{"1", "2"},
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeValues() expected: %#v\ngot: %#v", expected, actual)
+	}
+}
+
+func TestDecoder_DecodeValuesErrorUnused(t *testing.T) {
+	t.Parallel()
+
+	type Request struct {
+		Query string `mapstructure:"q"`
+	}
+
+	var actual Request
+	decoder, err := NewDecoder(&DecoderConfig{
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           &actual,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	err = decoder.DecodeValues(url.Values{"q": {"foo"}, "qq": {"bar"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid keys: qq") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDecodeValues_Conflict(t *testing.T) {
+	t.Parallel()
+
+	var actual map[string]interface{}
+	err := DecodeValues(url.Values{"a": {"1"}, "a[b]": {"2"}}, &actual)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseValuesKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key      string
+		expected []string
+	}{
+		{"a", []string{"a"}},
+		{"a[]", []string{"a"}},
+		{"a[b]", []string{"a", "b"}},
+		{"a[b][c]", []string{"a", "b", "c"}},
+		{"a[0][b][]", []string{"a", "0", "b"}},
+		{"[a]", []string{"[a]"}},
+		{"a[b", []string{"a[b"}},
+		{"a[b]c", []string{"a[b]c"}},
+		{"a[][b]", []string{"a[][b]"}},
+	}
+
+	for _, tc := range tests {
+		if actual := parseValuesKey(tc.key); !reflect.DeepEqual(tc.expected, actual) {
+			t.Errorf("parseValuesKey(%q) expected: %#v, got: %#v", tc.key, tc.expected, actual)
+		}
+	}
+}
+
+func TestDecodeValues_SparseIndexes(t *testing.T) {
+	t.Parallel()
+
+	type Request struct {
+		Names []string `mapstructure:"a"`
+		Ports [3]int   `mapstructure:"p"`
+	}
+
+	values, err := url.ParseQuery("a[0]=x&a[3]=y&p[2]=80")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var actual Request
+	if err := DecodeValues(values, &actual); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := Request{
+		Names: []string{"x", "", "", "y"},
+		Ports: [3]int{0, 0, 80},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("DecodeValues() expected: %#v\ngot: %#v", expected, actual)
+	}
+
+	cases := map[string]string{
+		"a[10000]=x":     "'a' index 10000 exceeds the limit of 9999",
+		"a[1]=x&a[01]=y": "'a' index 1 is set more than once",
+	}
+	for query, expectedErr := range cases {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		err = DecodeValues(values, &Request{})
+		if err == nil || !strings.Contains(err.Error(), expectedErr) {
+			t.Fatalf("%s: unexpected error: %v", query, err)
+		}
+	}
+}